@@ -1,73 +1,398 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+	"github.com/lucasassuncao/devcontainerwizard/internal/policy"
 
 	"github.com/spf13/cobra"
 )
 
 var convertCmd = newConvertCmd()
 
+// convertOptions holds the flags of the convert command.
+type convertOptions struct {
+	configFile  string
+	output      string
+	force       bool
+	check       bool
+	strict      bool
+	format      string
+	policy      string
+	profiles    []string
+	eachProfile []string
+	mountFormat string
+	// warnPassthrough reports the keys copied into devcontainer.json unchecked.
+	warnPassthrough bool
+	// liftRunArgs moves runArgs flags that have a typed field into it.
+	liftRunArgs bool
+	// pathsAsWritten keeps relative paths relative to the devcontainer.json
+	// folder instead of resolving them against the config file.
+	pathsAsWritten bool
+
+	// rebaseFrom is set for multi-config runs: with pathsAsWritten, the folder
+	// the config's relative paths were written for, rebased onto the output
+	// folder.
+	rebaseFrom string
+}
+
 func newConvertCmd() *cobra.Command {
-	var (
-		configFile string
-		output     string
-		force      bool
-	)
+	var opts convertOptions
 	cmd := &cobra.Command{
-		Use:           "convert",
-		Short:         "Convert config.yaml to a devcontainer.json file",
-		Long:          "Reads config.yaml (or the file given by --config) and writes a devcontainer.json to the path given by --output.",
+		Use:   "convert [config files or directories...]",
+		Short: "Convert config.yaml to a devcontainer.json file",
+		Long: "Reads config.yaml (or the file given by --config) and writes a devcontainer.json to the path given by --output.\n" +
+			"With config files or directories as arguments, or with --each-profile, each configuration is written to\n" +
+			".devcontainer/<name>/devcontainer.json instead, which VS Code offers in a configuration picker.\n" +
+			"With --check, nothing is written: the command exits with status 2 when the file on disk differs from what config.yaml produces.",
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runConvertE(cmd, configFile, output, force)
+			if err := checkFormat(cmd, opts.format); err != nil {
+				return err
+			}
+			if opts.mountFormat != "" && !slices.Contains(devcontainer.MountFormats, opts.mountFormat) {
+				err := fmt.Errorf("unknown mount format %q (want one of %s)", opts.mountFormat, strings.Join(devcontainer.MountFormats, ", "))
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+				return err
+			}
+			if len(args) > 0 || len(opts.eachProfile) > 0 {
+				if cmd.Flags().Changed("output") {
+					err := errors.New("--output cannot be used with multiple configurations; they are written to " + multiConfigDir + "/<name>/devcontainer.json")
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+					return err
+				}
+				jobs, err := multiJobs(opts, args)
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+					return err
+				}
+				return runConvertMultiE(cmd, opts, jobs)
+			}
+			if opts.check {
+				return runConvertCheckE(cmd, opts)
+			}
+			return runConvertE(cmd, opts)
 		},
 	}
-	cmd.Flags().StringVarP(&configFile, "config", "c", "config.yaml", "Config file path")
-	cmd.Flags().StringVarP(&output, "output", "o", ".devcontainer/devcontainer.json", "Output devcontainer.json file path")
-	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing output file")
+	cmd.Flags().StringVarP(&opts.configFile, "config", "c", "config.yaml", "Config file path")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", ".devcontainer/devcontainer.json", "Output devcontainer.json file path")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Overwrite existing output file")
+	cmd.Flags().BoolVar(&opts.check, "check", false, "Compare the output file with config.yaml without writing; exit 2 on drift")
+	cmd.Flags().BoolVar(&opts.strict, "strict", true, "Fail on unknown or misspelled keys (use --strict=false to only warn)")
+	cmd.Flags().StringVar(&opts.format, "format", "text", "Diagnostics format: text, json or sarif (json and sarif are written to stdout)")
+	cmd.Flags().StringArrayVar(&opts.profiles, "profile", nil, "Apply a profile from the profiles section (repeatable, applied in order)")
+	cmd.Flags().StringSliceVar(&opts.eachProfile, "each-profile", nil, "Write one configuration per profile to .devcontainer/<profile>/devcontainer.json (comma-separated or repeatable)")
+	cmd.Flags().StringVar(&opts.policy, "policy", "", "Policy file to enforce (default $"+policy.EnvVar+", then "+policy.DefaultFile+" in the repository root)")
+	cmd.Flags().StringVar(&opts.mountFormat, "mount-format", "", "Write every mount as an object or as a --mount string (default: as written)")
+	cmd.Flags().BoolVar(&opts.warnPassthrough, "warn-passthrough", false, "Warn about x-* keys and unknown customizations copied to devcontainer.json unchecked")
+	cmd.Flags().BoolVar(&opts.liftRunArgs, "lift-run-args", false, "Move --privileged, --init, --cap-add, --security-opt, --device and -e flags from runArgs into their typed fields")
+	cmd.Flags().BoolVar(&opts.pathsAsWritten, "paths-as-written", false, "Copy build and compose paths unchanged, relative to the devcontainer.json folder, instead of resolving them against config.yaml")
 	return cmd
 }
 
-func runConvertE(cmd *cobra.Command, configFile, output string, force bool) error {
-	k, err := devcontainer.LoadYAMLFile(configFile)
-	if err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to load config: %v\n", err)
-		return err
-	}
+func runConvertE(cmd *cobra.Command, opts convertOptions) error {
+	_, ds, err := convertOne(cmd, opts)
+	return finishReport(cmd, opts, opts.configFile, ds, err)
+}
 
-	dc, err := devcontainer.Parse(k)
-	if err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to parse config: %v\n", err)
+// finishReport writes the structured report, if one was requested, and
+// returns the outcome of the run. A report that only failed because ds holds
+// errors does not mask err.
+func finishReport(cmd *cobra.Command, opts convertOptions, files string, ds []diagnostic.Diagnostic, err error) error {
+	if opts.format == "text" {
 		return err
 	}
+	if rerr := writeDiagnostics(cmd, opts.format, files, ds); err == nil {
+		return rerr
+	}
+	return err
+}
 
-	if err := devcontainer.Validate(dc); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Invalid devcontainer config:\n%s\n", devcontainer.HumanizeValidationError(err))
-		return err
+// convertOne converts a single config file to opts.output. Findings are
+// returned for the structured formats and already reported in text mode.
+func convertOne(cmd *cobra.Command, opts convertOptions) (model.DevContainer, []diagnostic.Diagnostic, error) {
+	dc, ds, err := loadConfig(cmd, opts)
+	if err != nil {
+		return dc, ds, err
 	}
 
-	path, err := devcontainer.WriteFile(dc, output, force)
+	path, err := devcontainer.WriteFile(dc, opts.output, opts.force)
 	if err != nil {
 		fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to write devcontainer: %v\n", err)
-		return err
+		return dc, ds, err
 	}
 
-	clean := filepath.ToSlash(filepath.Clean(path))
-	canonical := clean == ".devcontainer/devcontainer.json" ||
-		clean == ".devcontainer.json" ||
-		strings.HasSuffix(clean, "/.devcontainer/devcontainer.json")
-	if !canonical {
+	if !canonicalOutput(path) {
 		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: output path is not a canonical devcontainer location.")
 		fmt.Fprintln(cmd.ErrOrStderr(), "VS Code and the devcontainer CLI won't auto-detect this file.")
-		fmt.Fprintln(cmd.ErrOrStderr(), "Canonical paths: .devcontainer/devcontainer.json or .devcontainer.json")
+		fmt.Fprintln(cmd.ErrOrStderr(), "Canonical paths: .devcontainer/devcontainer.json, .devcontainer.json or .devcontainer/<name>/devcontainer.json")
+	}
+
+	out := cmd.OutOrStdout()
+	if opts.format != "text" {
+		out = cmd.ErrOrStderr()
+	}
+	fmt.Fprintf(out, "Saved devcontainer to %s\n", path)
+	return dc, ds, nil
+}
+
+// canonicalOutput reports whether path is a location VS Code and the
+// devcontainer CLI look for, including the one-level subfolders used for
+// multiple configurations.
+func canonicalOutput(path string) bool {
+	clean := filepath.ToSlash(filepath.Clean(path))
+	if clean == ".devcontainer.json" || strings.HasSuffix(clean, "/.devcontainer.json") {
+		return true
+	}
+	if filepath.Base(clean) != "devcontainer.json" {
+		return false
 	}
+	dir := filepath.ToSlash(filepath.Dir(clean))
+	isRoot := func(d string) bool { return d == ".devcontainer" || strings.HasSuffix(d, "/.devcontainer") }
+	return isRoot(dir) || isRoot(filepath.ToSlash(filepath.Dir(dir)))
+}
+
+// loadConfig loads, parses and validates the config file. In text mode
+// findings are reported on stderr as they are found; in the structured formats
+// they are only collected, and the caller writes the report.
+func loadConfig(cmd *cobra.Command, opts convertOptions) (model.DevContainer, []diagnostic.Diagnostic, error) {
+	pol, err := loadPolicy(cmd, opts.policy)
+	if err != nil {
+		return model.DevContainer{}, nil, err
+	}
+	load := devcontainer.LoadOptions{Profiles: opts.profiles, PathsAsWritten: opts.pathsAsWritten}
+	dc, pos, ds := checkConfig(opts.configFile, load, opts.strict, pol)
+	if opts.mountFormat != "" && !diagnostic.HasErrors(ds) {
+		ds = append(ds, devcontainer.NormalizeMounts(&dc, opts.mountFormat, opts.configFile, pos)...)
+	}
+	if opts.liftRunArgs && !diagnostic.HasErrors(ds) {
+		ds = append(ds, devcontainer.LiftRunArgs(&dc, opts.configFile, pos)...)
+	}
+	if opts.warnPassthrough && !diagnostic.HasErrors(ds) {
+		ds = append(ds, devcontainer.PassthroughDiagnostics(&dc, opts.configFile, pos)...)
+	}
+	dir := pathBase(opts)
+	if !diagnostic.HasErrors(ds) {
+		ds = append(ds, devcontainer.PathDiagnostics(&dc, dir, opts.configFile, pos)...)
+		ds = append(ds, devcontainer.ComposeDiagnostics(&dc, dir, opts.configFile, pos)...)
+		ds = append(ds, devcontainer.BuildDiagnostics(&dc, dir, opts.configFile, pos)...)
+	}
+	if opts.format == "text" {
+		reportText(cmd, ds, pol)
+	}
+	if diagnostic.HasErrors(ds) {
+		return model.DevContainer{}, ds, fmt.Errorf("invalid devcontainer config %s", opts.configFile)
+	}
+	devcontainer.RebasePaths(&dc, dir, filepath.Dir(opts.output))
+	return dc, ds, nil
+}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Saved devcontainer to %s\n", path)
+// pathBase returns the folder the config's relative paths are written for:
+// the config file's own folder, or with --paths-as-written the folder of the
+// devcontainer.json (.devcontainer in multi-config runs, whose paths are then
+// rebased for the deeper folder).
+func pathBase(opts convertOptions) string {
+	switch {
+	case !opts.pathsAsWritten:
+		return filepath.Dir(opts.configFile)
+	case opts.rebaseFrom != "":
+		return opts.rebaseFrom
+	}
+	return filepath.Dir(opts.output)
+}
+
+// checkConfig runs every load, parse and validation step on file loaded with
+// load, then the policy checks when pol is non-nil, and
+// returns the findings as diagnostics along with the key positions used to
+// locate them. dc is only meaningful when none of the findings is an error.
+// Unknown keys are errors in strict mode and warnings otherwise.
+func checkConfig(file string, load devcontainer.LoadOptions, strict bool, pol *policy.Policy) (model.DevContainer, devcontainer.Positions, []diagnostic.Diagnostic) {
+	k, err := devcontainer.LoadConfig(file, load)
+	if err != nil {
+		return model.DevContainer{}, nil, []diagnostic.Diagnostic{devcontainer.ErrorDiagnostic(devcontainer.RuleLoad, err, file, nil)}
+	}
+	pos, err := devcontainer.LoadPositions(file, load.Profiles...)
+	if err != nil {
+		return model.DevContainer{}, nil, []diagnostic.Diagnostic{devcontainer.ErrorDiagnostic(devcontainer.RuleLoad, err, file, nil)}
+	}
+
+	var (
+		dc model.DevContainer
+		ds []diagnostic.Diagnostic
+	)
+	if strict {
+		dc, err = devcontainer.ParseStrict(k)
+	} else {
+		ds = devcontainer.UnknownKeyDiagnostics(devcontainer.UnknownKeys(k), file, pos, diagnostic.SeverityWarning)
+		dc, err = devcontainer.Parse(k)
+	}
+	var unknown *devcontainer.UnknownKeysError
+	if errors.As(err, &unknown) {
+		return model.DevContainer{}, pos, devcontainer.UnknownKeyDiagnostics(unknown.Keys, file, pos, diagnostic.SeverityError)
+	}
+	if err != nil {
+		return model.DevContainer{}, pos, append(ds, devcontainer.ErrorDiagnostic(devcontainer.RuleParse, err, file, pos))
+	}
+
+	ds = append(ds, devcontainer.ValidationDiagnostics(devcontainer.Validate(dc), file, pos)...)
+	ds = append(ds, devcontainer.VariableDiagnostics(&dc, file, pos)...)
+	ds = append(ds, devcontainer.PortAttributesDiagnostics(&dc, file, pos)...)
+	if pol != nil && !diagnostic.HasErrors(ds) {
+		ds = append(ds, pol.Evaluate(&dc, file, pos)...)
+	}
+	return dc, pos, ds
+}
+
+// reportText prints diagnostics in the human-readable form: warnings one per
+// line, load and parse failures as a single error, policy findings as a
+// "Policy violations" block naming pol's file, and everything else as an
+// "Invalid devcontainer config" block.
+func reportText(cmd *cobra.Command, ds []diagnostic.Diagnostic, pol *policy.Policy) {
+	stderr := cmd.ErrOrStderr()
+	diagnostic.Sort(ds)
+	var errs []diagnostic.Diagnostic
+	for _, d := range ds {
+		if d.Severity != diagnostic.SeverityError {
+			fmt.Fprintf(stderr, "Warning: %s: %s\n", d.Location(), d.Message)
+			continue
+		}
+		errs = append(errs, d)
+	}
+	if len(errs) == 0 {
+		return
+	}
+
+	switch errs[0].RuleID {
+	case devcontainer.RuleLoad:
+		fmt.Fprintf(stderr, "Error: failed to load config: %s\n", errs[0].Message)
+		return
+	case devcontainer.RuleParse:
+		fmt.Fprintf(stderr, "Error: failed to parse config: %s\n", errs[0].Message)
+		return
+	}
+
+	if pol != nil && strings.HasPrefix(errs[0].RuleID, "policy/") {
+		fmt.Fprintf(stderr, "Policy violations (%s):\n", pol.File)
+	} else {
+		fmt.Fprintln(stderr, "Invalid devcontainer config:")
+	}
+	for _, d := range errs {
+		fmt.Fprintf(stderr, "%s: %s\n", d.Location(), d.Message)
+	}
+	if errs[0].RuleID == devcontainer.RuleUnknownKey {
+		fmt.Fprintln(stderr, "\nFix the keys above or rerun with --strict=false to ignore them.")
+	}
+}
+
+// loadPolicy resolves and loads the policy to enforce, returning nil when
+// none is configured.
+func loadPolicy(cmd *cobra.Command, flag string) (*policy.Policy, error) {
+	file, err := policy.Resolve(flag, ".")
+	if err == nil && file == "" {
+		return nil, nil
+	}
+	var pol *policy.Policy
+	if err == nil {
+		pol, err = policy.Load(file)
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		return nil, err
+	}
+	return pol, nil
+}
+
+// checkFormat rejects a --format value that diagnostic.Write cannot render.
+func checkFormat(cmd *cobra.Command, format string) error {
+	if slices.Contains(diagnostic.Formats, format) {
+		return nil
+	}
+	err := fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(diagnostic.Formats, ", "))
+	fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+	return err
+}
+
+// writeDiagnostics writes ds to stdout in format, sorted by position, and
+// fails when any of them is an error.
+func writeDiagnostics(cmd *cobra.Command, format, file string, ds []diagnostic.Diagnostic) error {
+	diagnostic.Sort(ds)
+	if err := diagnostic.Write(cmd.OutOrStdout(), format, ds, cmd.Root().Version); err != nil {
+		return err
+	}
+	if diagnostic.HasErrors(ds) {
+		return fmt.Errorf("invalid devcontainer config %s", file)
+	}
 	return nil
 }
+
+// runConvertCheckE builds the devcontainer.json in memory and compares it with
+// output. Drift (including a missing file) returns an exitError with exitDrift.
+// In the structured formats each difference is reported as a "drift"
+// diagnostic instead of a diff.
+func runConvertCheckE(cmd *cobra.Command, opts convertOptions) error {
+	_, ds, err := checkOne(cmd, opts)
+	return finishReport(cmd, opts, opts.configFile, ds, err)
+}
+
+// checkOne compares a single config file with opts.output, like convertOne.
+func checkOne(cmd *cobra.Command, opts convertOptions) (model.DevContainer, []diagnostic.Diagnostic, error) {
+	output := opts.output
+	structured := opts.format != "text"
+	dc, ds, err := loadConfig(cmd, opts)
+	if err != nil {
+		return dc, ds, err
+	}
+
+	expected, err := devcontainer.Marshal(dc)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		return dc, ds, err
+	}
+
+	diffs, err := devcontainer.CompareFile(expected, output)
+	if errors.Is(err, os.ErrNotExist) {
+		if structured {
+			ds = append(ds, diagnostic.Diagnostic{
+				RuleID:   devcontainer.RuleDrift,
+				Severity: diagnostic.SeverityError,
+				Message:  "File does not exist; run 'devcontainerwizard convert' to create it.",
+				File:     output,
+			})
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Drift: %s does not exist — run 'devcontainerwizard convert' to create it\n", output)
+		}
+		return dc, ds, &exitError{code: exitDrift, err: err}
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		return dc, ds, err
+	}
+
+	if len(diffs) > 0 {
+		if structured {
+			ds = append(ds, devcontainer.DriftDiagnostics(diffs, output)...)
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Drift: %s does not match %s\n", output, opts.configFile)
+			fmt.Fprintf(cmd.ErrOrStderr(), "--- %s (on disk)\n+++ %s (generated)\n", output, opts.configFile)
+			for _, d := range diffs {
+				fmt.Fprintln(cmd.ErrOrStderr(), d)
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "\nRun 'devcontainerwizard convert --force' to regenerate it.")
+		}
+		return dc, ds, &exitError{code: exitDrift, err: fmt.Errorf("%s is out of date (%d difference(s))", output, len(diffs))}
+	}
+
+	if !structured {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", output)
+	}
+	return dc, ds, nil
+}
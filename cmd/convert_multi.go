@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+
+	"github.com/spf13/cobra"
+)
+
+// multiConfigDir is the folder whose subfolders hold one devcontainer.json
+// per configuration; VS Code offers them in a picker.
+const multiConfigDir = ".devcontainer"
+
+// convertJob is one configuration of a multi-config run.
+type convertJob struct {
+	name       string // subfolder under multiConfigDir
+	configFile string
+	profiles   []string // applied after --profile
+}
+
+// multiJobs expands the config files and directories in args, and the
+// --each-profile names, into one job per output folder. A directory
+// contributes its *.yaml files and each subfolder holding a config.yaml.
+func multiJobs(opts convertOptions, args []string) ([]convertJob, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		found, err := configsInDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no config files found in %s", arg)
+		}
+		files = append(files, found...)
+	}
+
+	var jobs []convertJob
+	if len(files) == 0 {
+		for _, p := range opts.eachProfile {
+			jobs = append(jobs, convertJob{name: p, configFile: opts.configFile, profiles: []string{p}})
+		}
+		return jobs, nil
+	}
+	for _, f := range files {
+		if len(opts.eachProfile) == 0 {
+			jobs = append(jobs, convertJob{name: configName(f), configFile: f})
+			continue
+		}
+		for _, p := range opts.eachProfile {
+			jobs = append(jobs, convertJob{name: configName(f) + "-" + p, configFile: f, profiles: []string{p}})
+		}
+	}
+	return jobs, nil
+}
+
+// configsInDir lists the config files of a directory, sorted. Dotfiles such as
+// the lint and policy configs are skipped.
+func configsInDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if e.IsDir() {
+			if _, err := os.Stat(filepath.Join(path, "config.yaml")); err == nil {
+				out = append(out, filepath.Join(path, "config.yaml"))
+			}
+			continue
+		}
+		if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// configName derives the output folder name of a config file: its base name
+// without extension, or its directory name for a file called config.yaml.
+func configName(file string) string {
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if base == "config" {
+		if dir := filepath.Base(filepath.Dir(file)); dir != "." && dir != string(filepath.Separator) {
+			return dir
+		}
+	}
+	return base
+}
+
+// runConvertMultiE converts (or with --check, compares) every job to
+// .devcontainer/<name>/devcontainer.json. Relative paths are rebased from
+// each config file's folder, or with --paths-as-written from .devcontainer,
+// where a single config is written, to the subfolder. Folder
+// collisions are disambiguated with a numeric suffix, and configurations
+// sharing the same name are reported since the picker cannot tell them apart.
+func runConvertMultiE(cmd *cobra.Command, opts convertOptions, jobs []convertJob) error {
+	stderr := cmd.ErrOrStderr()
+	taken := map[string]string{}  // folder -> config that claimed it
+	byName := map[string]string{} // devcontainer name -> output
+	var (
+		all     []diagnostic.Diagnostic
+		failed  error
+		drifted error
+		configs []string
+	)
+	for _, job := range jobs {
+		folder := job.name
+		for i := 2; taken[folder] != ""; i++ {
+			folder = job.name + "-" + strconv.Itoa(i)
+		}
+		if folder != job.name {
+			fmt.Fprintf(stderr, "Warning: %s and %s both map to %s; writing %s to %s instead\n",
+				taken[job.name], job.configFile, filepath.Join(multiConfigDir, job.name), job.configFile, filepath.Join(multiConfigDir, folder))
+		}
+		taken[folder] = job.configFile
+		configs = append(configs, job.configFile)
+
+		jo := opts
+		jo.configFile = job.configFile
+		jo.profiles = append(append([]string{}, opts.profiles...), job.profiles...)
+		jo.output = filepath.Join(multiConfigDir, folder, "devcontainer.json")
+		jo.rebaseFrom = multiConfigDir
+
+		run := convertOne
+		if opts.check {
+			run = checkOne
+		}
+		dc, ds, err := run(cmd, jo)
+		all = append(all, ds...)
+
+		var ee *exitError
+		switch {
+		case errors.As(err, &ee) && ee.code == exitDrift:
+			drifted = err
+		case err != nil:
+			failed = err
+		case dc.Name != "":
+			if other, ok := byName[dc.Name]; ok {
+				fmt.Fprintf(stderr, "Warning: %s and %s are both named %q; VS Code's configuration picker will not tell them apart\n",
+					other, jo.output, dc.Name)
+			}
+			byName[dc.Name] = jo.output
+		}
+	}
+
+	if failed == nil {
+		failed = drifted
+	}
+	return finishReport(cmd, opts, strings.Join(configs, ", "), all, failed)
+}
@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+
 	"github.com/spf13/cobra"
 )
 
@@ -49,7 +53,7 @@ func TestConvertCanonicalNoWarning(t *testing.T) {
 	if err := c.Execute(); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if strings.Contains(errOut.String(), "Warning") {
+	if strings.Contains(errOut.String(), "not part of the model") {
 		t.Errorf("expected no warning for canonical path, got: %s", errOut.String())
 	}
 	if _, err := os.Stat(filepath.Join(dir, ".devcontainer", "devcontainer.json")); err != nil {
@@ -97,3 +101,599 @@ func TestConvertMissingConfig(t *testing.T) {
 		t.Fatal("expected error for missing config, got nil")
 	}
 }
+
+func TestConvertCheckUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+
+	c, _ := setupConvertCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	// Reformatting and comments on disk are not drift.
+	path := filepath.Join(dir, ".devcontainer", "devcontainer.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonc := "// generated\n" + strings.ReplaceAll(string(data), "\n", " ")
+	if err := os.WriteFile(path, []byte(jsonc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"--check"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("expected no drift, got %v\n%s", err, errOut.String())
+	}
+}
+
+func TestConvertCheckDrift(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+
+	c, _ := setupConvertCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	body := "name: t\nimage: ubuntu:24.04\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"))
+
+	c, errOut := setupConvertCmd(t, []string{"--check"})
+	err := c.Execute()
+	var ee *exitError
+	if !errors.As(err, &ee) || ee.code != exitDrift {
+		t.Fatalf("expected drift exit code %d, got %v", exitDrift, err)
+	}
+	for _, want := range []string{`- image: "ubuntu:22.04"`, `+ image: "ubuntu:24.04"`} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("diff missing %q\nfull output: %s", want, errOut.String())
+		}
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"))
+	if !bytes.Equal(before, after) {
+		t.Error("--check must not write the output file")
+	}
+}
+
+func TestConvertCheckMissingOutput(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+
+	c, _ := setupConvertCmd(t, []string{"--check"})
+	var ee *exitError
+	if err := c.Execute(); !errors.As(err, &ee) || ee.code != exitDrift {
+		t.Fatalf("expected drift exit code for missing output, got %v", err)
+	}
+}
+
+func TestConvertStrictRejectsUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\npostCreateComand: ls\nbuild:\n  dockerfil: Dockerfile\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected error for unknown keys, got nil")
+	}
+	for _, want := range []string{
+		"Unknown key 'postCreateComand' (did you mean 'postCreateCommand'?)",
+		"Unknown key 'build.dockerfil' (did you mean 'build.dockerfile'?)",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".devcontainer", "devcontainer.json")); err == nil {
+		t.Error("output must not be written when strict decoding fails")
+	}
+}
+
+func TestConvertNonStrictWarnsOnUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nfowardPorts: [3000]\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"--strict=false"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "Warning: config.yaml:3:1: Unknown key 'fowardPorts' (did you mean 'forwardPorts'?)") {
+		t.Errorf("expected unknown-key warning, got: %s", errOut.String())
+	}
+}
+
+func TestConvertValidationErrorsHavePositions(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nmounts:\n  - type: bind\n    source: /a\n    target: /a\n  - type: bind\n    source: /b\nportsAttributes:\n  \"3000\":\n    protocol: ftp\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+	for _, want := range []string{
+		"config.yaml:7:5: Field 'mounts[1].target' is required.",
+		`config.yaml:11:5: Field 'portsAttributes."3000".protocol' must be one of the following values: http https.`,
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+		}
+	}
+}
+
+func TestConvertRejectsBadVariables(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\ncontainerEnv:\n  HOME_COPY: ${localenv:HOME}\n  P: ${containerEnv:PATH}\nremoteEnv:\n  PATH: ${containerEnv:PATH}:/x\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected variable error, got nil")
+	}
+	for _, want := range []string{
+		`config.yaml:4:3: Field 'containerEnv."HOME_COPY"' uses unknown variable '${localenv:HOME}' (did you mean 'localEnv'?).`,
+		`config.yaml:5:3: Field 'containerEnv."P"' cannot use '${containerEnv:PATH}'`,
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+		}
+	}
+	if strings.Contains(errOut.String(), "Field 'remoteEnv") {
+		t.Errorf("remoteEnv may use ${containerEnv:...}\nfull output: %s", errOut.String())
+	}
+}
+
+func TestConvertMountFormat(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nmounts:\n  - src=/a,dst=/a,type=bind,ro\n  - type: volume\n    source: v\n    target: /v\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for format, want := range map[string]string{
+		"string": `"source=/a,target=/a,type=bind,readonly",
+    "source=v,target=/v,type=volume"`,
+		"object": `"type": "bind",
+      "source": "/a",
+      "target": "/a",
+      "readonly": true`,
+	} {
+		c, errOut := setupConvertCmd(t, []string{"-o", "out.json", "-f", "--mount-format", format})
+		if err := c.Execute(); err != nil {
+			t.Fatalf("%s: unexpected error: %v\n%s", format, err, errOut.String())
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "out.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s: output missing %s\n%s", format, want, data)
+		}
+	}
+}
+
+func TestConvertFormatJSON(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\ndockerFile: Dockerfile\nmounts:\n  - type: bind\n    source: /a\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := setupConvertCmd(t, []string{"--format", "json"})
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+
+	var ds []diagnostic.Diagnostic
+	if err := json.Unmarshal(out.Bytes(), &ds); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, out.String())
+	}
+	rules := map[string]diagnostic.Diagnostic{}
+	for _, d := range ds {
+		rules[d.RuleID] = d
+	}
+	if d, ok := rules["mutually_exclusive"]; !ok || d.Severity != diagnostic.SeverityError || d.File != "config.yaml" {
+		t.Errorf("missing struct-level mutually_exclusive error: %+v", ds)
+	}
+	if d, ok := rules["required"]; !ok || d.Path != "mounts[0].target" || d.Line != 5 || d.Column != 5 {
+		t.Errorf("required diagnostic = %+v, want mounts[0].target at 5:5", d)
+	}
+}
+
+func TestConvertFormatSARIFClean(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+
+	c, _ := setupConvertCmd(t, []string{"--format", "sarif"})
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []any `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("stdout is not SARIF: %v\n%s", err, out.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 0 {
+		t.Errorf("unexpected SARIF log: %s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".devcontainer", "devcontainer.json")); err != nil {
+		t.Errorf("output not written: %v", err)
+	}
+}
+
+func TestConvertCheckFormatJSONReportsDrift(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, "out.json"), []byte(`{"name": "other"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := setupConvertCmd(t, []string{"--check", "--format", "json", "-o", "out.json"})
+	out := new(bytes.Buffer)
+	c.SetOut(out)
+	err := c.Execute()
+	var ee *exitError
+	if !errors.As(err, &ee) || ee.code != exitDrift {
+		t.Fatalf("expected drift exit, got %v", err)
+	}
+
+	var ds []diagnostic.Diagnostic
+	if err := json.Unmarshal(out.Bytes(), &ds); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, out.String())
+	}
+	found := false
+	for _, d := range ds {
+		if d.RuleID == "drift" && d.Path == "name" && d.File == "out.json" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("missing drift diagnostic for name: %+v", ds)
+	}
+}
+
+func TestConvertUnknownFormat(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+
+	c, errOut := setupConvertCmd(t, []string{"--format", "xml"})
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	if !strings.Contains(errOut.String(), `unknown format "xml"`) {
+		t.Errorf("unexpected stderr: %s", errOut.String())
+	}
+}
+
+func TestConvertEnforcesPolicy(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	t.Setenv("DEVCONTAINERWIZARD_POLICY", "")
+	writeMinimalConfig(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, ".devcontainerwizard-policy.yaml"), []byte("allowedRegistries: [mcr.microsoft.com]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected policy violation, got nil")
+	}
+	for _, want := range []string{
+		"Policy violations (.devcontainerwizard-policy.yaml):",
+		"config.yaml:2:1: Image 'ubuntu:22.04' is not from an allowed registry (allowed: mcr.microsoft.com).",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".devcontainer", "devcontainer.json")); err == nil {
+		t.Error("output written despite policy violation")
+	}
+}
+
+func TestConvertAppliesProfiles(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nprofiles:\n  codespaces:\n    hostRequirements:\n      cpus: 4\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"--profile", "codespaces", "-o", "out.json"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"cpus": 4`) || strings.Contains(string(data), "profiles") {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+func TestConvertMultipleConfigs(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	files := map[string]string{
+		"configs/python/config.yaml": "name: Python\nbuild:\n  dockerfile: Dockerfile\n  context: ..\n",
+		"configs/node.yaml":          "name: Node\nimage: node:20\n",
+		"configs/other/node.yaml":    "name: Node\nimage: node:22\n",
+		".devcontainer/Dockerfile":   "FROM ubuntu:22.04\n",
+	}
+	for name, body := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"configs", "configs/other/node.yaml", "--paths-as-written"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".devcontainer", "python", "devcontainer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"dockerfile": "../Dockerfile"`) || !strings.Contains(string(data), `"context": "../.."`) {
+		t.Errorf("paths not rebased:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".devcontainer", "node-2", "devcontainer.json")); err != nil {
+		t.Errorf("colliding config not disambiguated: %v", err)
+	}
+	for _, want := range []string{
+		"both map to .devcontainer/node",
+		`are both named "Node"`,
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+		}
+	}
+	if strings.Contains(errOut.String(), "not a canonical devcontainer location") {
+		t.Errorf("subfolder outputs must count as canonical:\n%s", errOut.String())
+	}
+}
+
+func TestConvertResolvesPathsAgainstConfig(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	for _, d := range []string{".git", "docker"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docker", "Dockerfile"), []byte("FROM ubuntu:22.04\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	body := "name: t\nbuild:\n  dockerfile: docker/Dockerfile\n  context: .\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"dockerfile": "../docker/Dockerfile"`) || !strings.Contains(string(data), `"context": ".."`) {
+		t.Errorf("paths not rewritten for the output folder:\n%s", data)
+	}
+
+	c, errOut = setupConvertCmd(t, []string{"-f", "--paths-as-written"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	if want := "Warning: config.yaml:3:3: Field 'build.dockerfile': Dockerfile 'docker/Dockerfile' does not exist (looked for .devcontainer/docker/Dockerfile)."; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+	}
+
+	body = "name: t\nbuild:\n  dockerfile: docker/Dockerfile\n  context: ..\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, errOut = setupConvertCmd(t, []string{"-f"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	if want := "Warning: config.yaml:4:3: Field 'build.context': '..' resolves to"; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "outside the repository") {
+		t.Errorf("escaping path not reported:\n%s", errOut.String())
+	}
+}
+
+func TestConvertResolvesBasePathsAgainstBase(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	if err := os.MkdirAll(filepath.Join(dir, "base"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"base/Dockerfile": "FROM ubuntu:22.04\n",
+		"base/base.yaml":  "build:\n  dockerfile: Dockerfile\n  context: .\n",
+		"config.yaml":     "extends: base/base.yaml\nname: t\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"dockerfile": "../base/Dockerfile"`) || !strings.Contains(string(data), `"context": "../base"`) {
+		t.Errorf("base paths not resolved against the base file:\n%s", data)
+	}
+}
+
+func TestConvertEachProfile(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nprofiles:\n  local: {}\n  codespaces:\n    name: t (Codespaces)\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"--each-profile", "local,codespaces"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	for _, p := range []string{"local", "codespaces"} {
+		if _, err := os.Stat(filepath.Join(dir, ".devcontainer", p, "devcontainer.json")); err != nil {
+			t.Errorf("profile %s not written: %v", p, err)
+		}
+	}
+}
+
+func TestConvertWarnPassthrough(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nx-owner: infra\ncustomizations:\n  gitpod:\n    openMode: split-right\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"-o", "out.json"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	if strings.Contains(errOut.String(), "not part of the model") {
+		t.Errorf("passthrough keys warned without --warn-passthrough:\n%s", errOut.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"x-owner": "infra"`) || !strings.Contains(string(data), `"openMode": "split-right"`) {
+		t.Errorf("passthrough keys dropped:\n%s", data)
+	}
+
+	c, errOut = setupConvertCmd(t, []string{"-o", "out.json", "-f", "--warn-passthrough"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	for _, want := range []string{
+		"Warning: config.yaml:3:1: Key 'x-owner' is not part of the model",
+		"Warning: config.yaml:5:3: Key 'customizations.gitpod' is not part of the model",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr missing %q:\n%s", want, errOut.String())
+		}
+	}
+}
+
+func TestConvertLiftRunArgs(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nrunArgs:\n  - --privileged\n  - --cap-add=SYS_PTRACE\n  - --network=host\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, errOut := setupConvertCmd(t, []string{"-o", "out.json", "--lift-run-args"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"privileged": true`, `"SYS_PTRACE"`, `"--network=host"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %s:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "--privileged") || strings.Contains(string(data), "--cap-add") {
+		t.Errorf("lifted flags left in runArgs:\n%s", data)
+	}
+}
+
+func TestConvertChecksComposeServices(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	if err := os.MkdirAll(filepath.Join(dir, ".devcontainer"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	compose := "services:\n  app:\n    image: ubuntu:22.04\n  db:\n    image: postgres:16\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(compose), 0600); err != nil {
+		t.Fatal(err)
+	}
+	body := "name: t\ndockerComposeFile: docker-compose.yml\nservice: app\nrunServices: [db]\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, errOut := setupConvertCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+
+	body = "name: t\ndockerComposeFile: [docker-compose.yml, docker-compose.dev.yml]\nservice: api\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, errOut = setupConvertCmd(t, []string{"-f"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("a missing compose file should only warn: %v\n%s", err, errOut.String())
+	}
+	if want := "Warning: config.yaml:2:41: Field 'dockerComposeFile[1]': compose file 'docker-compose.dev.yml' does not exist"; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+	}
+
+	body = "name: t\ndockerComposeFile: docker-compose.yml\nservice: api\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, errOut = setupConvertCmd(t, []string{"-f"})
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected an unknown service to fail")
+	}
+	if want := "config.yaml:3:1: Field 'service' names service 'api', which the compose files do not define (did you mean 'app'?)."; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+
+	"github.com/spf13/cobra"
+)
+
+var importCmd = newImportCmd()
+
+func newImportCmd() *cobra.Command {
+	var (
+		input  string
+		output string
+		force  bool
+	)
+	cmd := &cobra.Command{
+		Use:           "import",
+		Short:         "Convert an existing devcontainer.json into config.yaml",
+		Long:          "Reads a devcontainer.json (comments and trailing commas allowed) and writes the equivalent config.yaml, keeping JSONC comments as YAML comments where possible.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImportE(cmd, input, output, force)
+		},
+	}
+	cmd.Flags().StringVarP(&input, "input", "i", ".devcontainer/devcontainer.json", "devcontainer.json file to import")
+	cmd.Flags().StringVarP(&output, "output", "o", "config.yaml", "Output config file path")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing output file")
+	return cmd
+}
+
+func runImportE(cmd *cobra.Command, input, output string, force bool) error {
+	if _, err := os.Stat(output); err == nil && !force {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: file '%s' already exists — use --force to overwrite\n", output)
+		return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+	}
+
+	content, err := devcontainer.ImportFile(input)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to import devcontainer: %v\n", err)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), 0750); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: creating output directory: %v\n", err)
+		return err
+	}
+	if err := os.WriteFile(output, content, 0600); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error writing config file: %v\n", err)
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %q into %q.\n\nNext: devcontainerwizard edit -c %s\n", input, output, output)
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func setupImportCmd(t *testing.T, args []string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	errBuf := new(bytes.Buffer)
+	c := newImportCmd()
+	c.SetOut(new(bytes.Buffer))
+	c.SetErr(errBuf)
+	c.SetArgs(args)
+	return c, errBuf
+}
+
+func writeDevcontainerJSON(t *testing.T, dir, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".devcontainer"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportJSONCWithComments(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeDevcontainerJSON(t, dir, `{
+  // image first in JSON, name first in YAML
+  "image": "ubuntu:22.04",
+  "name": "t", // project name
+  "forwardPorts": [3000, 5432,],
+  "postCreateCommand": ["npm", "ci"],
+}`)
+
+	c, errOut := setupImportCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"name: t # project name\n",
+		"# image first in JSON, name first in YAML\nimage: ubuntu:22.04\n",
+		"forwardPorts:\n  - 3000\n  - 5432\n",
+		"postCreateCommand:\n  - npm\n  - ci\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("config.yaml missing %q\nfull output:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "name:") > strings.Index(got, "image:") {
+		t.Errorf("expected name before image (model.TopLevelKeys order), got:\n%s", got)
+	}
+}
+
+func TestImportRoundTripsThroughConvert(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeDevcontainerJSON(t, dir, `{"name": "t", "image": "ubuntu:22.04", "mounts": ["source=a,target=/a,type=bind"]}`)
+
+	c, _ := setupImportCmd(t, nil)
+	if err := c.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	cc, errOut := setupConvertCmd(t, []string{"-o", "out/devcontainer.json"})
+	if err := cc.Execute(); err != nil {
+		t.Fatalf("convert after import: %v\n%s", err, errOut.String())
+	}
+}
+
+func TestImportRefusesToOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeMinimalConfig(t, dir)
+	writeDevcontainerJSON(t, dir, `{"name": "t", "image": "ubuntu:22.04"}`)
+
+	c, _ := setupImportCmd(t, nil)
+	err := c.Execute()
+	if err == nil {
+		t.Fatal("expected error when config.yaml exists, got nil")
+	}
+	if !strings.Contains(err.Error(), "config.yaml already exists (use --force to overwrite)") {
+		t.Errorf("error should name the file and mention --force, got %q", err)
+	}
+}
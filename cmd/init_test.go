@@ -124,3 +124,24 @@ func TestInitInvalidTemplate(t *testing.T) {
 		t.Fatal("expected error for invalid template, got nil")
 	}
 }
+
+func TestInitTemplatesConvertBeforeFilesExist(t *testing.T) {
+	for _, tmpl := range []string{"dockerfile", "dockercompose"} {
+		t.Run(tmpl, func(t *testing.T) {
+			dir := t.TempDir()
+			chdir(t, dir)
+
+			c, _, _ := setupInitCmd(t, []string{"--template", tmpl})
+			if err := c.Execute(); err != nil {
+				t.Fatalf("init: %v", err)
+			}
+			cc, errOut := setupConvertCmd(t, nil)
+			if err := cc.Execute(); err != nil {
+				t.Fatalf("convert: %v\n%s", err, errOut.String())
+			}
+			if !strings.Contains(errOut.String(), "does not exist") {
+				t.Errorf("expected a warning about the missing file, got: %s", errOut.String())
+			}
+		})
+	}
+}
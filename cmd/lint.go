@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/lint"
+	"github.com/lucasassuncao/devcontainerwizard/internal/policy"
+
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = newLintCmd()
+
+// lintOptions holds the flags of the lint command.
+type lintOptions struct {
+	configFile string
+	lintConfig string
+	strict     bool
+	format     string
+	policy     string
+	profiles   []string
+}
+
+func newLintCmd() *cobra.Command {
+	var opts lintOptions
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check config.yaml for risky devcontainer settings",
+		Long: "Validates config.yaml and then runs the lint rules over it, reporting settings such as privileged containers or a mounted Docker socket.\n" +
+			"Rule severities are read from --lint-config (default " + lint.DefaultConfigFile + " when present); single findings can be silenced with a\n" +
+			"'# devcontainerwizard:ignore <rule>' comment on the offending line.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(cmd, opts.format); err != nil {
+				return err
+			}
+			return runLintE(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.configFile, "config", "c", "config.yaml", "Config file path")
+	cmd.Flags().StringVar(&opts.lintConfig, "lint-config", "", "Lint config file (default "+lint.DefaultConfigFile+" if it exists)")
+	cmd.Flags().BoolVar(&opts.strict, "strict", true, "Fail on unknown or misspelled keys (use --strict=false to only warn)")
+	cmd.Flags().StringVar(&opts.format, "format", "text", "Diagnostics format: text, json or sarif")
+	cmd.Flags().StringArrayVar(&opts.profiles, "profile", nil, "Apply a profile from the profiles section (repeatable, applied in order)")
+	cmd.Flags().StringVar(&opts.policy, "policy", "", "Policy file to enforce (default $"+policy.EnvVar+", then "+policy.DefaultFile+" in the repository root)")
+	return cmd
+}
+
+func runLintE(cmd *cobra.Command, opts lintOptions) error {
+	cfgPath, optional := opts.lintConfig, false
+	if cfgPath == "" {
+		cfgPath, optional = lint.DefaultConfigFile, true
+	}
+	cfg, err := lint.LoadConfig(cfgPath, optional)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		return err
+	}
+
+	pol, err := loadPolicy(cmd, opts.policy)
+	if err != nil {
+		return err
+	}
+
+	dc, pos, ds := checkConfig(opts.configFile, devcontainer.LoadOptions{Profiles: opts.profiles}, opts.strict, nil)
+	if !diagnostic.HasErrors(ds) {
+		sup, err := lint.LoadSuppressions(opts.configFile)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return err
+		}
+		ds = append(ds, lint.Run(&dc, cfg, opts.configFile, pos, sup)...)
+		if pol != nil {
+			ds = append(ds, pol.Evaluate(&dc, opts.configFile, pos)...)
+		}
+	}
+
+	err = writeDiagnostics(cmd, opts.format, opts.configFile, ds)
+	if opts.format == "text" {
+		if len(ds) == 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "No problems found in %s\n", opts.configFile)
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%d problem(s) found in %s\n", len(ds), opts.configFile)
+		}
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+
+	"github.com/spf13/cobra"
+)
+
+func setupLintCmd(t *testing.T, args []string) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	out, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	c := newLintCmd()
+	c.SetOut(out)
+	c.SetErr(errBuf)
+	c.SetArgs(args)
+	return c, out, errBuf
+}
+
+func TestLintReportsRiskySettings(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nprivileged: true\nremoteUser: root\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, out, _ := setupLintCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected privileged to fail the lint")
+	}
+	for _, want := range []string{
+		"config.yaml:3:1: error: Container runs privileged",
+		"config.yaml:4:1: warning: remoteUser is root",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("stdout missing %q\nfull output: %s", want, out.String())
+		}
+	}
+}
+
+func TestLintConfigAndSuppression(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\nprivileged: true # devcontainerwizard:ignore privileged\nremoteUser: root\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".devcontainerwizard-lint.yaml"), []byte("rules:\n  remote-user-root: off\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, out, errOut := setupLintCmd(t, []string{"--format", "json"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	var ds []diagnostic.Diagnostic
+	if err := json.Unmarshal(out.Bytes(), &ds); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, out.String())
+	}
+	if len(ds) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", ds)
+	}
+}
+
+func TestLintReportsValidationErrors(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("image: ubuntu:22.04\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, out, _ := setupLintCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+	if !strings.Contains(out.String(), "Field 'name' is required. [required]") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
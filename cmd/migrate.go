@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+)
+
+var migrateCmd = newMigrateCmd()
+
+// migrateOptions holds the flags of the migrate command.
+type migrateOptions struct {
+	configFile string
+	dryRun     bool
+}
+
+func newMigrateCmd() *cobra.Command {
+	var opts migrateOptions
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite deprecated fields in config.yaml",
+		Long: "Rewrites deprecated fields of config.yaml and its profiles in place, keeping comments:\n" +
+			"dockerFile becomes build.dockerfile, appPort becomes forwardPorts with portsAttributes,\n" +
+			"and --mount strings become mount objects. Each change is printed; with --dry-run nothing is written.\n" +
+			"A rewritten file keeps its comments but loses its blank lines and is re-indented with two spaces.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateE(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.configFile, "config", "c", "config.yaml", "Config file path")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the changes without writing them")
+	return cmd
+}
+
+func runMigrateE(cmd *cobra.Command, opts migrateOptions) error {
+	data, err := os.ReadFile(opts.configFile)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to load config: %v\n", err)
+		return err
+	}
+	migrated, ms, err := devcontainer.Migrate(data)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to load config: %v\n", err)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	changes := 0
+	for _, m := range ms {
+		if m.Skipped {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s:%s: %s\n", opts.configFile, m.Pos, m.Message)
+			continue
+		}
+		changes++
+		fmt.Fprintf(out, "%s:%s: %s\n", opts.configFile, m.Pos, m.Message)
+	}
+
+	switch {
+	case changes == 0:
+		fmt.Fprintf(out, "Nothing to migrate in %s.\n", opts.configFile)
+		return nil
+	case opts.dryRun:
+		fmt.Fprintf(out, "Dry run: %d change(s) not written to %s.\n", changes, opts.configFile)
+		return nil
+	}
+
+	info, err := os.Stat(opts.configFile)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error writing config file: %v\n", err)
+		return err
+	}
+	if err := os.WriteFile(opts.configFile, migrated, info.Mode().Perm()); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error writing config file: %v\n", err)
+		return err
+	}
+	fmt.Fprintf(out, "Migrated %s: %d change(s).\n", opts.configFile, changes)
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateDryRunAndWrite(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "# keep me\nname: t\ndockerFile: Dockerfile\nappPort: 3000\n"
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+		c := newMigrateCmd()
+		c.SetOut(out)
+		c.SetErr(errOut)
+		c.SetArgs(args)
+		if err := c.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+		}
+		return out.String()
+	}
+
+	out := run("--dry-run")
+	for _, want := range []string{
+		"config.yaml:3:1: Replaced 'dockerFile' with 'build.dockerfile' and context '.'.",
+		"config.yaml:4:1: Moved 1 port from 'appPort' to 'forwardPorts' and 'portsAttributes'.",
+		"Dry run: 2 change(s) not written to config.yaml.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != body {
+		t.Fatalf("--dry-run modified the file:\n%s", data)
+	}
+
+	if out := run(); !strings.Contains(out, "Migrated config.yaml: 2 change(s).") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# keep me\n") || !strings.Contains(string(data), "build:\n  dockerfile: Dockerfile\n") {
+		t.Errorf("migrated file:\n%s", data)
+	}
+	if out := run(); !strings.Contains(out, "Nothing to migrate in config.yaml.") {
+		t.Errorf("second run: %s", out)
+	}
+}
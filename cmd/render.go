@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+
+	"github.com/spf13/cobra"
+)
+
+var renderCmd = newRenderCmd()
+
+// renderOptions holds the flags of the render command.
+type renderOptions struct {
+	configFile string
+	output     string
+	workspace  string
+	strict     bool
+	profiles   []string
+	// pathsAsWritten keeps relative paths relative to the devcontainer.json
+	// folder, as for convert.
+	pathsAsWritten bool
+}
+
+func newRenderCmd() *cobra.Command {
+	var opts renderOptions
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Print the devcontainer.json with variables resolved for this host",
+		Long: "Loads config.yaml and prints the devcontainer.json it converts to, with ${localEnv:...}, ${localWorkspaceFolder},\n" +
+			"${containerWorkspaceFolder}, ${devcontainerId} and the other spec variables replaced by the values they take on this\n" +
+			"machine. References that resolve to an empty string are reported on stderr. Nothing is written to disk.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRenderE(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.configFile, "config", "c", "config.yaml", "Config file path")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", ".devcontainer/devcontainer.json", "Where the devcontainer.json would be written (determines ${devcontainerId})")
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", ".", "Local workspace folder opened in the editor")
+	cmd.Flags().BoolVar(&opts.strict, "strict", true, "Fail on unknown or misspelled keys (use --strict=false to only warn)")
+	cmd.Flags().StringArrayVar(&opts.profiles, "profile", nil, "Apply a profile from the profiles section (repeatable, applied in order)")
+	cmd.Flags().BoolVar(&opts.pathsAsWritten, "paths-as-written", false, "Copy build and compose paths unchanged, relative to the devcontainer.json folder, instead of resolving them against config.yaml")
+	return cmd
+}
+
+func runRenderE(cmd *cobra.Command, opts renderOptions) error {
+	load := devcontainer.LoadOptions{Profiles: opts.profiles, PathsAsWritten: opts.pathsAsWritten}
+	dc, pos, ds := checkConfig(opts.configFile, load, opts.strict, nil)
+	if diagnostic.HasErrors(ds) {
+		reportText(cmd, ds, nil)
+		return fmt.Errorf("invalid devcontainer config %s", opts.configFile)
+	}
+	dir := pathBase(convertOptions{configFile: opts.configFile, output: opts.output, pathsAsWritten: opts.pathsAsWritten})
+	devcontainer.RebasePaths(&dc, dir, filepath.Dir(opts.output))
+
+	workspace, err := filepath.Abs(opts.workspace)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		return err
+	}
+	host := devcontainer.Host{
+		LocalWorkspaceFolder: workspace,
+		ConfigFile:           filepath.Join(workspace, opts.output),
+	}
+	if filepath.IsAbs(opts.output) {
+		host.ConfigFile = opts.output
+	}
+	ds = append(ds, devcontainer.Substitute(&dc, host, opts.configFile, pos)...)
+	reportText(cmd, ds, nil)
+
+	data, err := devcontainer.Marshal(dc)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func setupRenderCmd(t *testing.T, args []string) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	out, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	c := newRenderCmd()
+	c.SetOut(out)
+	c.SetErr(errBuf)
+	c.SetArgs(args)
+	return c, out, errBuf
+}
+
+func TestRenderResolvesVariables(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	t.Setenv("RENDER_TEST_USER", "alice")
+	body := "name: t\nimage: ubuntu:22.04\n" +
+		"workspaceMount: source=${localWorkspaceFolder},target=/src/${localWorkspaceFolderBasename},type=bind\n" +
+		"workspaceFolder: /src/${localWorkspaceFolderBasename}\n" +
+		"containerEnv:\n  USER_NAME: ${localEnv:RENDER_TEST_USER}\n  TOKEN: ${localEnv:RENDER_TEST_UNSET}\n" +
+		"remoteEnv:\n  WHO: ${containerEnv:USER_NAME}\n  PATH: ${containerEnv:PATH}:/x\n  WS: ${containerWorkspaceFolder}\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, out, errOut := setupRenderCmd(t, []string{"--workspace", dir})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	var got struct {
+		WorkspaceMount string            `json:"workspaceMount"`
+		ContainerEnv   map[string]string `json:"containerEnv"`
+		RemoteEnv      map[string]string `json:"remoteEnv"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	base := filepath.Base(dir)
+	if want := "source=" + dir + ",target=/src/" + base + ",type=bind"; got.WorkspaceMount != want {
+		t.Errorf("workspaceMount = %q, want %q", got.WorkspaceMount, want)
+	}
+	if got.ContainerEnv["USER_NAME"] != "alice" || got.RemoteEnv["WHO"] != "alice" {
+		t.Errorf("env not resolved: %+v %+v", got.ContainerEnv, got.RemoteEnv)
+	}
+	if got.RemoteEnv["PATH"] != "${containerEnv:PATH}:/x" {
+		t.Errorf("remoteEnv.PATH = %q, want it left for the container", got.RemoteEnv["PATH"])
+	}
+	if got.RemoteEnv["WS"] != "/src/"+base {
+		t.Errorf("remoteEnv.WS = %q", got.RemoteEnv["WS"])
+	}
+	if want := `config.yaml:7:3: Field 'containerEnv."TOKEN"': '${localEnv:RENDER_TEST_UNSET}' resolves to an empty string.`; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr missing %q\nfull output: %s", want, errOut.String())
+	}
+}
+
+func TestRenderInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	body := "name: t\nimage: ubuntu:22.04\ncontainerEnv:\n  A: ${localenv:HOME}\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, out, errOut := setupRenderCmd(t, nil)
+	if err := c.Execute(); err == nil {
+		t.Fatal("expected error for an unknown variable")
+	}
+	if out.Len() != 0 || !strings.Contains(errOut.String(), "did you mean 'localEnv'?") {
+		t.Errorf("stdout: %s\nstderr: %s", out.String(), errOut.String())
+	}
+}
+
+func TestRenderMatchesConvert(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	if err := os.MkdirAll(filepath.Join(dir, "docker"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docker", "Dockerfile"), []byte("FROM ubuntu:22.04\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		body string
+		args []string
+	}{
+		{"name: t\nbuild:\n  dockerfile: docker/Dockerfile\n  context: .\n", nil},
+		{"name: t\nbuild:\n  dockerfile: ../docker/Dockerfile\n  context: ..\n", []string{"--paths-as-written"}},
+	}
+	for _, tc := range cases {
+		if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(tc.body), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cc, errOut := setupConvertCmd(t, append([]string{"-f"}, tc.args...))
+		if err := cc.Execute(); err != nil {
+			t.Fatalf("%v: convert: %v\n%s", tc.args, err, errOut.String())
+		}
+		rc, out, errOut := setupRenderCmd(t, append([]string{"--workspace", dir}, tc.args...))
+		if err := rc.Execute(); err != nil {
+			t.Fatalf("%v: render: %v\n%s", tc.args, err, errOut.String())
+		}
+		data, err := os.ReadFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(out.String()); got != string(data) {
+			t.Errorf("%v: render output differs from convert\nrender:\n%s\nconvert:\n%s", tc.args, got, data)
+		}
+	}
+}
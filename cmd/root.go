@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 
 	"github.com/lucasassuncao/devcontainerwizard/cmd/docs"
@@ -21,12 +22,34 @@ func Execute(version string) {
 		docs.GenerateCmd,
 		docs.ShowCmd,
 		docs.ShowExamplesCmd,
+		importCmd,
 		initCmd,
+		lintCmd,
+		migrateCmd,
+		renderCmd,
 		selfUpdateCmd(version),
 		editCmd,
 	)
 
 	if err := rootCmd.Execute(); err != nil {
+		var ee *exitError
+		if errors.As(err, &ee) {
+			os.Exit(ee.code)
+		}
 		os.Exit(1)
 	}
 }
+
+// exitDrift is the exit status of `convert --check` when the devcontainer.json
+// on disk no longer matches config.yaml. Any other failure exits with 1.
+const exitDrift = 2
+
+// exitError carries a specific process exit status through cobra's RunE so
+// Execute can tell outcomes such as drift apart from ordinary failures.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
@@ -0,0 +1,107 @@
+package devcontainer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RuleComposeFile identifies a dockerComposeFile entry that cannot be read,
+// and RuleComposeService a service name the compose files do not define.
+const (
+	RuleComposeFile    = "compose-file"
+	RuleComposeService = "compose-service"
+)
+
+// ComposeDiagnostics checks dc against the Docker Compose files it names,
+// resolved against dir, the folder of the devcontainer.json. The services of
+// all files are merged in order, as docker compose does. Invalid files and a
+// service or runServices entry no file defines are errors. A missing file is a
+// warning, since a config is often written before the compose file it names,
+// and so is a forwardPorts "service:port" naming an unknown service, since the
+// host may live outside the compose project. Service names are only checked
+// when every file could be read.
+func ComposeDiagnostics(dc *model.DevContainer, dir, file string, pos Positions) []diagnostic.Diagnostic {
+	files := dc.DockerComposeFile.Values()
+	if len(files) == 0 {
+		return nil
+	}
+
+	var out []diagnostic.Diagnostic
+	services := map[string]bool{}
+	complete := true
+	for i, f := range files {
+		path := "dockerComposeFile"
+		if !dc.DockerComposeFile.Scalar {
+			path = joinIndex(path, i)
+		}
+		if strings.Contains(f, "${") {
+			complete = false
+			continue
+		}
+		resolved := resolvePath(dir, f)
+		names, err := readComposeServices(resolved)
+		if err != nil {
+			complete = false
+			sev, msg := diagnostic.SeverityError, fmt.Sprintf("Field '%s': cannot read compose file '%s': %v.", path, f, err)
+			if errors.Is(err, os.ErrNotExist) {
+				sev, msg = diagnostic.SeverityWarning, fmt.Sprintf("Field '%s': compose file '%s' does not exist (looked for %s).", path, f, filepath.ToSlash(resolved))
+			}
+			out = append(out, newDiagnostic(RuleComposeFile, sev, file, pos, path, msg))
+			continue
+		}
+		for _, name := range names {
+			services[name] = true
+		}
+	}
+	if !complete {
+		return out
+	}
+
+	report := func(sev diagnostic.Severity, path, name string) {
+		msg := fmt.Sprintf("Field '%s' names service '%s', which the compose files do not define", path, name)
+		if s := closest(name, SortedKeys(services)); s != "" {
+			msg += fmt.Sprintf(" (did you mean '%s'?)", s)
+		}
+		out = append(out, newDiagnostic(RuleComposeService, sev, file, pos, path, msg+"."))
+	}
+	if dc.Service != "" && !services[dc.Service] {
+		report(diagnostic.SeverityError, "service", dc.Service)
+	}
+	for i, name := range dc.RunServices {
+		if !services[name] {
+			report(diagnostic.SeverityError, joinIndex("runServices", i), name)
+		}
+	}
+	for i, p := range dc.ForwardPorts {
+		host, _, err := p.Port()
+		if err != nil || host == "" || host == "localhost" || net.ParseIP(host) != nil || services[host] {
+			continue
+		}
+		report(diagnostic.SeverityWarning, joinIndex("forwardPorts", i), host)
+	}
+	return out
+}
+
+// readComposeServices returns the service names defined in a compose file.
+func readComposeServices(path string) ([]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path named by the user's config
+	if err != nil {
+		return nil, err
+	}
+	var compose struct {
+		Services map[string]any `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, err
+	}
+	return SortedKeys(compose.Services), nil
+}
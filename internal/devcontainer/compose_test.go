@@ -0,0 +1,40 @@
+package devcontainer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestComposeDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"compose.yml":  "services:\n  app:\n    image: ubuntu\n  db:\n    image: postgres\n",
+		"override.yml": "services:\n  cache:\n    image: redis\n",
+	})
+	dc := &model.DevContainer{
+		DockerComposeFile: model.StringSlicePtr([]string{"compose.yml", "override.yml"}),
+		Service:           "ap",
+		RunServices:       []string{"db", "cache", "worker"},
+		ForwardPorts: []model.PortSpec{
+			model.PortNumber(3000), model.PortString("db:5432"), model.PortString("dbb:5432"),
+			model.PortString("localhost:80"), model.PortString("127.0.0.1:81"),
+		},
+	}
+	var got []string
+	for _, d := range ComposeDiagnostics(dc, dir, "config.yaml", nil) {
+		got = append(got, string(d.Severity)+" "+d.Path)
+	}
+	want := []string{"error service", "error runServices[2]", "warning forwardPorts[2]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	dc.DockerComposeFile = model.SingleStringPtr("missing.yml")
+	ds := ComposeDiagnostics(dc, dir, "config.yaml", nil)
+	if len(ds) != 1 || ds[0].RuleID != RuleComposeFile || ds[0].Path != "dockerComposeFile" || ds[0].Severity != diagnostic.SeverityWarning {
+		t.Errorf("missing file: %v", ds)
+	}
+}
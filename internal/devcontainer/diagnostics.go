@@ -0,0 +1,148 @@
+package devcontainer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+)
+
+// RuleUnknownKey, RuleLoad and RuleParse identify the diagnostics produced
+// before validation runs, and RuleDrift the differences found by CompareFile.
+// Validation diagnostics use the validator tag ("required", "oneof",
+// "one_required", …) as their rule ID.
+const (
+	RuleUnknownKey = "unknown-key"
+	RuleLoad       = "load-error"
+	RuleParse      = "parse-error"
+	RuleDrift      = "drift"
+)
+
+// newDiagnostic builds a diagnostic for path, resolving its position in pos.
+func newDiagnostic(rule string, sev diagnostic.Severity, file string, pos Positions, path, msg string) diagnostic.Diagnostic {
+	d := diagnostic.Diagnostic{
+		RuleID:   rule,
+		Severity: sev,
+		Message:  msg,
+		File:     file,
+		Path:     path,
+	}
+	pos.Place(&d)
+	return d
+}
+
+// Place sets the line and column of d from the position of d.Path in p and,
+// when the key was inherited from an extends base file, points d.File at that
+// file.
+func (p Positions) Place(d *diagnostic.Diagnostic) {
+	at, ok := p.Lookup(d.Path)
+	if !ok {
+		return
+	}
+	d.Line, d.Column = at.Line, at.Column
+	if at.File != "" {
+		d.File = at.File
+	}
+}
+
+// UnknownKeyDiagnostics converts UnknownKeys results into diagnostics with the
+// given severity (an error in strict mode, a warning otherwise).
+func UnknownKeyDiagnostics(keys []UnknownKey, file string, pos Positions, sev diagnostic.Severity) []diagnostic.Diagnostic {
+	out := make([]diagnostic.Diagnostic, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, newDiagnostic(RuleUnknownKey, sev, file, pos, k.Path, k.String()))
+	}
+	return out
+}
+
+// ValidationDiagnostics converts the error returned by Validate into one
+// diagnostic per failed rule, an error unless the tag is listed in
+// validationWarnings. Errors that are not validator errors
+// become a single diagnostic carrying err's message.
+func ValidationDiagnostics(err error, file string, pos Positions) []diagnostic.Diagnostic {
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return []diagnostic.Diagnostic{newDiagnostic("validation", diagnostic.SeverityError, file, pos, "", err.Error())}
+	}
+
+	out := make([]diagnostic.Diagnostic, 0, len(errs))
+	for _, e := range errs {
+		tmpl, ok := validationMessages[e.Tag()]
+		if !ok {
+			tmpl = "Field '%[1]s' failed validation '" + e.Tag() + "'."
+		}
+		path := FieldPath(e.Namespace())
+		msg := tmpl
+		if strings.Contains(tmpl, "%[") {
+			msg = fmt.Sprintf(tmpl, path, e.Param())
+		}
+		sev := diagnostic.SeverityError
+		if validationWarnings[e.Tag()] {
+			sev = diagnostic.SeverityWarning
+		}
+		out = append(out, newDiagnostic(e.Tag(), sev, file, pos, path, msg))
+	}
+	return out
+}
+
+var (
+	yamlLineRe   = regexp.MustCompile(`line (\d+)`)
+	decodeNameRe = regexp.MustCompile(`'([A-Za-z][^']*)'`)
+)
+
+// ErrorDiagnostic wraps a failure from LoadYAMLFile (RuleLoad) or Parse
+// (RuleParse) as a diagnostic, recovering the line number from YAML syntax
+// errors and the field from mapstructure decoding errors when possible.
+func ErrorDiagnostic(rule string, err error, file string, pos Positions) diagnostic.Diagnostic {
+	d := diagnostic.Diagnostic{
+		RuleID:   rule,
+		Severity: diagnostic.SeverityError,
+		Message:  err.Error(),
+		File:     file,
+	}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		return d
+	}
+	if m := decodeNameRe.FindStringSubmatch(err.Error()); m != nil && rule == RuleParse {
+		path := FieldPath("DevContainer." + m[1])
+		if _, ok := pos.Lookup(path); ok && path != "" {
+			d.Path = path
+			pos.Place(&d)
+		}
+	}
+	return d
+}
+
+// DriftDiagnostics reports each difference between the devcontainer.json at
+// file and the generated one as an error diagnostic.
+func DriftDiagnostics(diffs []Difference, file string) []diagnostic.Diagnostic {
+	out := make([]diagnostic.Diagnostic, 0, len(diffs))
+	for _, d := range diffs {
+		var msg string
+		switch d.Kind {
+		case "added":
+			msg = fmt.Sprintf("Key '%s' is missing from the file (generated: %s).", d.Path, compactJSON(d.Expected))
+		case "removed":
+			msg = fmt.Sprintf("Key '%s' is not generated from the config (on disk: %s).", d.Path, compactJSON(d.Disk))
+		default:
+			msg = fmt.Sprintf("Key '%s' differs (on disk: %s, generated: %s).", d.Path, compactJSON(d.Disk), compactJSON(d.Expected))
+		}
+		out = append(out, diagnostic.Diagnostic{
+			RuleID:   RuleDrift,
+			Severity: diagnostic.SeverityError,
+			Message:  msg,
+			File:     file,
+			Path:     d.Path,
+		})
+	}
+	return out
+}
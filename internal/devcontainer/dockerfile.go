@@ -0,0 +1,246 @@
+package devcontainer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// Rules reported by BuildDiagnostics.
+const (
+	RuleBuildFile   = "build-file"   // Dockerfile or context missing
+	RuleBuildTarget = "build-target" // target names no stage of the Dockerfile
+	RuleBuildArg    = "build-arg"    // args entry the Dockerfile never declares
+	RuleCacheFrom   = "cache-from"   // cacheFrom entry that is no image or cache reference
+)
+
+// Dockerfile is what BuildDiagnostics needs from a Dockerfile: its named
+// stages, lowercased as Docker matches them, and every ARG it declares.
+type Dockerfile struct {
+	Stages []string
+	Args   map[string]bool
+}
+
+// predefinedArgs are build args Docker accepts without an ARG instruction.
+var predefinedArgs = map[string]bool{
+	"HTTP_PROXY": true, "http_proxy": true, "HTTPS_PROXY": true, "https_proxy": true,
+	"FTP_PROXY": true, "ftp_proxy": true, "NO_PROXY": true, "no_proxy": true,
+	"ALL_PROXY": true, "all_proxy": true,
+}
+
+// BuildDiagnostics checks the build section of dc, or the legacy dockerFile,
+// against the files it names. As the spec defines, the Dockerfile and the
+// context are resolved against dir, the folder of the devcontainer.json. A
+// target that names no stage, a malformed cacheFrom entry and an unreadable
+// Dockerfile fail the build and are errors. A missing Dockerfile or context
+// is a warning, since a config is often written before the files it names,
+// and an arg the Dockerfile never declares is only ignored by Docker and is a
+// warning too. Paths containing ${...} variables are not checked.
+func BuildDiagnostics(dc *model.DevContainer, dir, file string, pos Positions) []diagnostic.Diagnostic {
+	var out []diagnostic.Diagnostic
+	errorf := func(rule, path, format string, args ...any) {
+		out = append(out, newDiagnostic(rule, diagnostic.SeverityError, file, pos, path, fmt.Sprintf(format, args...)))
+	}
+	warnf := func(rule, path, format string, args ...any) {
+		out = append(out, newDiagnostic(rule, diagnostic.SeverityWarning, file, pos, path, fmt.Sprintf(format, args...)))
+	}
+
+	dockerfile, dockerfilePath := dc.DockerFile, "dockerFile"
+	b := dc.Build
+	if b != nil {
+		dockerfile, dockerfilePath = b.Dockerfile, "build.dockerfile"
+		if b.Context != "" && !strings.Contains(b.Context, "${") {
+			if info, err := os.Stat(resolvePath(dir, b.Context)); err != nil || !info.IsDir() {
+				warnf(RuleBuildFile, "build.context", "Field 'build.context': directory '%s' does not exist (looked for %s).", b.Context, filepath.ToSlash(resolvePath(dir, b.Context)))
+			}
+		}
+		for i, ref := range b.CacheFrom.Values() {
+			path := "build.cacheFrom"
+			if !b.CacheFrom.Scalar {
+				path = joinIndex(path, i)
+			}
+			if err := checkCacheFrom(ref); err != nil {
+				errorf(RuleCacheFrom, path, "Field '%s' is not a valid cache source: %v.", path, err)
+			}
+		}
+	}
+	if dockerfile == "" || strings.Contains(dockerfile, "${") {
+		return out
+	}
+
+	resolved := resolvePath(dir, dockerfile)
+	data, err := os.ReadFile(resolved) // #nosec G304 -- path named by the user's config
+	if errors.Is(err, os.ErrNotExist) {
+		warnf(RuleBuildFile, dockerfilePath, "Field '%s': Dockerfile '%s' does not exist (looked for %s).", dockerfilePath, dockerfile, filepath.ToSlash(resolved))
+		return out
+	}
+	if err != nil {
+		errorf(RuleBuildFile, dockerfilePath, "Field '%s': cannot read Dockerfile '%s': %v.", dockerfilePath, dockerfile, err)
+		return out
+	}
+	if b == nil {
+		return out
+	}
+	df := ParseDockerfile(data)
+
+	if b.Target != "" && !containsFold(df.Stages, b.Target) {
+		msg := fmt.Sprintf("Field 'build.target': '%s' names no stage of %s", b.Target, dockerfile)
+		if s := closest(strings.ToLower(b.Target), df.Stages); s != "" {
+			msg += fmt.Sprintf(" (did you mean '%s'?)", s)
+		} else if len(df.Stages) > 0 {
+			msg += fmt.Sprintf(" (stages: %s)", strings.Join(df.Stages, ", "))
+		}
+		errorf(RuleBuildTarget, "build.target", "%s.", msg)
+	}
+	for _, name := range SortedKeys(b.Args) {
+		if df.Args[name] || predefinedArgs[name] || strings.HasPrefix(name, "BUILDKIT_") {
+			continue
+		}
+		path := joinMapKey("build.args", name)
+		msg := fmt.Sprintf("Field '%s': %s declares no ARG %s, so Docker ignores it", path, dockerfile, name)
+		if s := closest(name, SortedKeys(df.Args)); s != "" {
+			msg += fmt.Sprintf(" (did you mean '%s'?)", s)
+		}
+		out = append(out, newDiagnostic(RuleBuildArg, diagnostic.SeverityWarning, file, pos, path, msg+"."))
+	}
+	return out
+}
+
+// ParseDockerfile reads the stage names and ARG declarations of a
+// Dockerfile. It understands the escape parser directive, line
+// continuations, comments and heredocs, which is enough to find FROM and
+// ARG instructions; it does not validate the file.
+func ParseDockerfile(data []byte) Dockerfile {
+	df := Dockerfile{Args: map[string]bool{}}
+	escape := `\`
+	var (
+		heredoc string
+		logical strings.Builder
+		first   = true
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		trimmed := strings.TrimSpace(sc.Text())
+		if heredoc != "" {
+			if strings.TrimLeft(trimmed, "\t") == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		if first {
+			if m := escapeDirectiveRe.FindStringSubmatch(trimmed); m != nil {
+				escape = m[1]
+				continue
+			}
+			first = trimmed == "" || strings.HasPrefix(trimmed, "#")
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, escape) {
+			logical.WriteString(strings.TrimSuffix(trimmed, escape) + " ")
+			continue
+		}
+		logical.WriteString(trimmed)
+		instr := logical.String()
+		logical.Reset()
+		if m := heredocRe.FindStringSubmatch(instr); m != nil {
+			heredoc = m[1]
+		}
+		df.addInstruction(instr)
+	}
+	df.addInstruction(logical.String())
+	return df
+}
+
+var (
+	escapeDirectiveRe = regexp.MustCompile("^#\\s*escape\\s*=\\s*([\\\\`])\\s*$")
+	heredocRe         = regexp.MustCompile(`<<-?["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+)
+
+func (df *Dockerfile) addInstruction(instr string) {
+	fields := strings.Fields(instr)
+	if len(fields) < 2 {
+		return
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "FROM":
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 3 && strings.EqualFold(args[1], "AS") {
+			df.Stages = append(df.Stages, strings.ToLower(args[2]))
+		}
+	case "ARG":
+		for _, f := range fields[1:] {
+			name, _, _ := strings.Cut(f, "=")
+			if envNameRe.MatchString(name) {
+				df.Args[name] = true
+			}
+		}
+	}
+}
+
+// imageRefRe matches a Docker image reference: an optional registry host,
+// a lowercase repository path, and an optional tag and digest.
+var imageRefRe = regexp.MustCompile(`^(?:[A-Za-z0-9.-]+(?::[0-9]+)?/)?[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*(?::[A-Za-z0-9_][A-Za-z0-9_.-]{0,127})?(?:@sha256:[0-9a-f]{64})?$`)
+
+// cacheTypes are the cache backends buildx accepts in "type=..." sources.
+var cacheTypes = map[string]bool{"registry": true, "local": true, "gha": true, "s3": true, "azblob": true, "inline": true}
+
+// checkCacheFrom accepts an image reference or a buildx cache source such as
+// "type=registry,ref=ghcr.io/acme/app:cache".
+func checkCacheFrom(ref string) error {
+	if strings.Contains(ref, "${") {
+		return nil
+	}
+	if !strings.Contains(ref, "=") {
+		if !imageRefRe.MatchString(ref) {
+			return fmt.Errorf("'%s' is not an image reference", ref)
+		}
+		return nil
+	}
+	attrs := map[string]string{}
+	for _, kv := range strings.Split(ref, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("'%s' is not a key=value pair", kv)
+		}
+		attrs[k] = v
+	}
+	typ, ok := attrs["type"]
+	switch {
+	case !ok:
+		return fmt.Errorf("cache source '%s' has no type", ref)
+	case !cacheTypes[typ]:
+		return fmt.Errorf("unknown cache type '%s'", typ)
+	case typ == "registry" && !imageRefRe.MatchString(attrs["ref"]):
+		return fmt.Errorf("'%s' is not an image reference", attrs["ref"])
+	}
+	return nil
+}
+
+func resolvePath(dir, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
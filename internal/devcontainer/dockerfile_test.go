@@ -0,0 +1,63 @@
+package devcontainer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	df := ParseDockerfile([]byte("# escape=`\n" +
+		"ARG BASE=ubuntu:22.04\n" +
+		"FROM --platform=$BUILDPLATFORM ${BASE} AS Base\n" +
+		"ARG GO_VERSION=1.22 `\n    NODE_VERSION\n" +
+		"# ARG COMMENTED\n" +
+		"RUN <<EOT\nFROM fake AS nope\nARG NOPE\nEOT\n" +
+		"from base as dev\n" +
+		"FROM scratch\n"))
+	if want := []string{"base", "dev"}; !reflect.DeepEqual(df.Stages, want) {
+		t.Errorf("stages = %v, want %v", df.Stages, want)
+	}
+	want := map[string]bool{"BASE": true, "GO_VERSION": true, "NODE_VERSION": true}
+	if !reflect.DeepEqual(df.Args, want) {
+		t.Errorf("args = %v, want %v", df.Args, want)
+	}
+}
+
+func TestBuildDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".devcontainer/Dockerfile": "FROM ubuntu:22.04 AS base\nARG GO_VERSION\nFROM base AS dev\n",
+	})
+	dc := &model.DevContainer{Build: &model.BuildConfig{
+		Dockerfile: "Dockerfile",
+		Context:    "..",
+		Target:     "devv",
+		Args:       map[string]string{"GO_VERSION": "1.23", "GO_VERSON": "1.23", "HTTP_PROXY": "x"},
+		CacheFrom: model.StringSlicePtr([]string{
+			"ghcr.io/acme/app:cache", "type=registry,ref=ghcr.io/acme/app:buildcache", "type=gha",
+			"Acme/App", "type=foo", "ref=ghcr.io/acme/app",
+		}),
+	}}
+	var got []string
+	for _, d := range BuildDiagnostics(dc, dir+"/.devcontainer", "config.yaml", nil) {
+		got = append(got, d.RuleID+" "+string(d.Severity)+" "+d.Path)
+	}
+	want := []string{
+		"cache-from error build.cacheFrom[3]", "cache-from error build.cacheFrom[4]", "cache-from error build.cacheFrom[5]",
+		"build-target error build.target", `build-arg warning build.args."GO_VERSON"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	dc.Build = &model.BuildConfig{Dockerfile: "missing/Dockerfile", Context: "nowhere"}
+	got = nil
+	for _, d := range BuildDiagnostics(dc, dir+"/.devcontainer", "config.yaml", nil) {
+		got = append(got, d.RuleID+" "+string(d.Severity)+" "+d.Path)
+	}
+	if want := []string{"build-file warning build.context", "build-file warning build.dockerfile"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
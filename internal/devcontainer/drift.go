@@ -0,0 +1,133 @@
+package devcontainer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Difference is one semantic mismatch between a generated devcontainer.json
+// and the file on disk. Path uses dotted keys and [i] indices.
+type Difference struct {
+	Path     string
+	Kind     string // "added", "removed" or "changed"
+	Disk     any    // value on disk; unset for "added"
+	Expected any    // value generated from config.yaml; unset for "removed"
+}
+
+// String renders d as a unified-diff style pair of lines: "-" for the value on
+// disk, "+" for the value config.yaml produces.
+func (d Difference) String() string {
+	var sb strings.Builder
+	if d.Kind != "added" {
+		fmt.Fprintf(&sb, "- %s: %s\n", d.Path, compactJSON(d.Disk))
+	}
+	if d.Kind != "removed" {
+		fmt.Fprintf(&sb, "+ %s: %s\n", d.Path, compactJSON(d.Expected))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// CompareFile compares the generated JSON in expected with the devcontainer.json
+// at path. The comparison is semantic: key order, whitespace, comments and
+// trailing commas on disk are ignored. A missing file is reported as an error
+// wrapping os.ErrNotExist.
+func CompareFile(expected []byte, path string) ([]Difference, error) {
+	disk, err := os.ReadFile(path) // #nosec G304 -- user-supplied output path
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	clean, _ := StripJSONC(disk)
+
+	var got, want any
+	if err := json.Unmarshal(clean, &got); err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	if err := json.Unmarshal(expected, &want); err != nil {
+		return nil, fmt.Errorf("error decoding generated JSON: %w", err)
+	}
+
+	var diffs []Difference
+	diffValues("", got, want, &diffs)
+	return diffs, nil
+}
+
+// diffValues appends the differences between disk and expected under path.
+func diffValues(path string, disk, expected any, diffs *[]Difference) {
+	dm, dok := disk.(map[string]any)
+	em, eok := expected.(map[string]any)
+	if dok && eok {
+		keys := make(map[string]bool, len(dm)+len(em))
+		for k := range dm {
+			keys[k] = true
+		}
+		for k := range em {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			p := joinPath(path, k)
+			dv, inDisk := dm[k]
+			ev, inExpected := em[k]
+			switch {
+			case !inDisk:
+				*diffs = append(*diffs, Difference{Path: p, Kind: "added", Expected: ev})
+			case !inExpected:
+				*diffs = append(*diffs, Difference{Path: p, Kind: "removed", Disk: dv})
+			default:
+				diffValues(p, dv, ev, diffs)
+			}
+		}
+		return
+	}
+
+	ds, dok := disk.([]any)
+	es, eok := expected.([]any)
+	if dok && eok {
+		for i := 0; i < len(ds) || i < len(es); i++ {
+			p := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(ds):
+				*diffs = append(*diffs, Difference{Path: p, Kind: "added", Expected: es[i]})
+			case i >= len(es):
+				*diffs = append(*diffs, Difference{Path: p, Kind: "removed", Disk: ds[i]})
+			default:
+				diffValues(p, ds[i], es[i], diffs)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(disk, expected) {
+		*diffs = append(*diffs, Difference{Path: path, Kind: "changed", Disk: disk, Expected: expected})
+	}
+}
+
+// joinPath appends key to a dotted path. Keys that contain dots (feature IDs,
+// VS Code settings) are quoted so the path stays unambiguous.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]\" ") {
+		key = strconv.Quote(key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// compactJSON renders v on a single line for diff output.
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -19,7 +19,7 @@ func FieldSnippets() map[string]map[string]string {
 		"customizations": {
 			"vscode":     "  vscode:\n    extensions:\n      - ms-python.python\n    settings:\n      editor.formatOnSave: true\n",
 			"jetbrains":  "  jetbrains:\n    plugins:\n      - org.rust.lang\n",
-			"codespaces": "  codespaces:\n    extensions:\n      - github.copilot\n    settings:\n      editor.formatOnSave: true\n",
+			"codespaces": "  codespaces:\n    extensions:\n      - github.copilot\n    settings:\n      editor.formatOnSave: true\n    repositories:\n      my-org/my-repo:\n        permissions:\n          contents: read\n    openFiles:\n      - README.md\n",
 		},
 		"watch": {
 			"waitFor": "  waitFor:\n    - postCreateCommand\n",
@@ -0,0 +1,424 @@
+package devcontainer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// ExtendsKey is the top-level key naming the config file(s) a config.yaml
+// inherits from, and ProfilesKey the section of named overlays selected at
+// convert time. Both are resolved by LoadYAMLFile and never reach Parse.
+const (
+	ExtendsKey  = "extends"
+	ProfilesKey = "profiles"
+)
+
+// DeleteTag and ReplaceTag are the YAML tags that override the default merge
+// of an inherited value: `key: !delete` removes the inherited key, and
+// `key: !replace [...]` replaces an inherited list or map instead of merging.
+const (
+	DeleteTag  = "!delete"
+	ReplaceTag = "!replace"
+)
+
+// Sentinels standing in for the tags after decoding, so that anchors and
+// merge keys keep working through the regular yaml.v3 decoder.
+const (
+	deleteSentinel = "\x00devcontainerwizard:delete"
+	replaceKey     = "\x00devcontainerwizard:replace"
+)
+
+// argumentLists are appended without de-duplication when merged: their items
+// are positional CLI arguments ("--cap-add", "SYS_PTRACE") where repeating
+// a value is meaningful.
+var argumentLists = map[string]bool{
+	"runArgs":       true,
+	"build.options": true,
+}
+
+// commandFields are the lifecycle command keys. A command given as a list is
+// a single argv, so an overriding list replaces the inherited one.
+var commandFields = func() map[string]bool {
+	out := map[string]bool{}
+	t := reflect.TypeOf(model.DevContainer{})
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); derefType(f.Type) == reflect.TypeOf(model.CommandValue{}) {
+			out[yamlName(f)] = true
+		}
+	}
+	return out
+}()
+
+// layer is a config map along with the origin of each of its keys.
+type layer struct {
+	values map[string]any
+	origin *origin
+}
+
+// overlays maps a profile name to its overlays, one per file of the extends
+// chain that defines it, base files first. They are kept unmerged so that
+// DeleteTag and ReplaceTag still apply when the profile is selected.
+type overlays map[string][]layer
+
+// loadConfigTree loads the config file at path with its extends chain and
+// the profiles of opts merged in, and returns the merged map along with the
+// origin of every key.
+func loadConfigTree(path string, opts LoadOptions) (map[string]any, *origin, error) {
+	merged, defined, err := loadMerged(path, nil, !opts.PathsAsWritten)
+	if err != nil {
+		return nil, nil, err
+	}
+	if merged, err = applyProfiles(merged, defined, opts.Profiles); err != nil {
+		return nil, nil, err
+	}
+	return merged.values, merged.origin, nil
+}
+
+// loadMerged reads the YAML file at path and applies its extends chain. stack
+// holds the absolute paths of the files currently being resolved, for cycle
+// detection. With rebase, the relative paths of each base file, including its
+// profiles, are rebased onto the folder of path before they are merged. The
+// profiles of every file are returned separately.
+func loadMerged(path string, stack []string, rebase bool) (layer, overlays, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return layer{}, nil, err
+	}
+	for i, p := range stack {
+		if p == abs {
+			chain := append(append([]string{}, stack[i:]...), abs)
+			for j := range chain {
+				chain[j] = filepath.Base(chain[j])
+			}
+			return layer{}, nil, fmt.Errorf("extends cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+	file := ""
+	if len(stack) > 0 {
+		file = path
+	}
+	stack = append(stack, abs)
+
+	m, o, err := readYAMLMap(path, file)
+	if err != nil {
+		return layer{}, nil, err
+	}
+
+	parents, err := extendsList(m[ExtendsKey])
+	if err != nil {
+		return layer{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	own, err := profileOverlays(m[ProfilesKey])
+	if err != nil {
+		return layer{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	delete(m, ExtendsKey)
+	delete(m, ProfilesKey)
+
+	base, profiles := layer{values: map[string]any{}}, overlays{}
+	for _, parent := range parents {
+		if !filepath.IsAbs(parent) {
+			parent = filepath.Join(filepath.Dir(path), parent)
+		}
+		pl, pp, err := loadMerged(parent, stack, rebase)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return layer{}, nil, fmt.Errorf("%s: extends %s: %w", path, parent, err)
+			}
+			return layer{}, nil, err
+		}
+		if rebase {
+			from, err := filepath.Abs(filepath.Dir(parent))
+			if err != nil {
+				return layer{}, nil, err
+			}
+			to := filepath.Dir(abs)
+			pl.values = rebaseMap(pl.values, from, to)
+			for _, l := range pp {
+				for i := range l {
+					l[i].values = rebaseMap(l[i].values, from, to)
+				}
+			}
+		}
+		base = mergeLayers(base, pl)
+		for name, l := range pp {
+			profiles[name] = append(profiles[name], l...)
+		}
+	}
+	for name, values := range own {
+		profiles[name] = append(profiles[name], layer{values: values, origin: o.key(ProfilesKey).key(name)})
+	}
+	return mergeLayers(base, layer{values: m, origin: o}), profiles, nil
+}
+
+// profileOverlays reads the profiles section of a single file.
+func profileOverlays(v any) (map[string]map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	section, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map of profile names to config overlays, got %T", ProfilesKey, v)
+	}
+	out := make(map[string]map[string]any, len(section))
+	for name, o := range section {
+		overlay, ok := o.(map[string]any)
+		if !ok && o != nil {
+			return nil, fmt.Errorf("%s.%s must be a config overlay (a map), got %T", ProfilesKey, name, o)
+		}
+		out[name] = overlay
+	}
+	return out, nil
+}
+
+// applyProfiles merges the overlays of the named profiles onto l, in order.
+func applyProfiles(l layer, profiles overlays, names []string) (layer, error) {
+	for _, name := range names {
+		layers, ok := profiles[name]
+		if !ok {
+			return layer{}, fmt.Errorf("unknown profile '%s' (available: %s)", name, strings.Join(SortedKeys(profiles), ", "))
+		}
+		for _, o := range layers {
+			l = mergeLayers(l, o)
+		}
+	}
+	return l, nil
+}
+
+// extendsList accepts a single path or a list of paths, applied in order.
+func extendsList(v any) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		out := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s[%d] must be a file path, got %T", ExtendsKey, i, item)
+			}
+			out[i] = s
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%s must be a file path or a list of file paths, got %T", ExtendsKey, v)
+}
+
+// readYAMLMap decodes the YAML file at path into a map, turning DeleteTag and
+// ReplaceTag nodes into sentinels for mergeMaps, and records the origin of
+// every key with file as its Position.File.
+func readYAMLMap(path, file string) (map[string]any, *origin, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied config path
+	if err != nil {
+		return nil, nil, err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	m := map[string]any{}
+	if root.Kind == 0 {
+		return m, &origin{kind: yaml.MappingNode}, nil
+	}
+	o := newOrigin(&root, Position{}, file)
+	quoteFeatureVersions(&root)
+	markTags(&root)
+	if err := root.Decode(&m); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, o, nil
+}
+
+// quoteFeatureVersions retags the unquoted numbers in features, and in the
+// features of each profile, as strings: in "go:1: 1.20" the version is
+// "1.20", which decoding as a float would turn into 1.2.
+func quoteFeatureVersions(root *yaml.Node) {
+	if len(root.Content) == 0 {
+		return
+	}
+	configs := []*yaml.Node{root.Content[0]}
+	if i := mappingIndex(root.Content[0], "profiles"); i >= 0 {
+		profiles := root.Content[0].Content[i+1]
+		for j := 1; j < len(profiles.Content) && profiles.Kind == yaml.MappingNode; j += 2 {
+			configs = append(configs, profiles.Content[j])
+		}
+	}
+	for _, c := range configs {
+		i := mappingIndex(c, "features")
+		if i < 0 || c.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+		features := c.Content[i+1]
+		for j := 1; j < len(features.Content); j += 2 {
+			if v := features.Content[j]; v.Kind == yaml.ScalarNode && (v.Tag == "!!int" || v.Tag == "!!float") {
+				v.Tag = "!!str"
+			}
+		}
+	}
+}
+
+func markTags(n *yaml.Node) {
+	switch n.Tag {
+	case DeleteTag:
+		*n = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: deleteSentinel, Line: n.Line, Column: n.Column}
+		return
+	case ReplaceTag:
+		inner := *n
+		inner.Tag = ""
+		*n = yaml.Node{
+			Kind: yaml.MappingNode,
+			Tag:  "!!map",
+			Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: replaceKey},
+				&inner,
+			},
+		}
+		n = &inner
+	}
+	for _, c := range n.Content {
+		markTags(c)
+	}
+}
+
+// mergeLayers merges over onto base with mergeMaps.
+func mergeLayers(base, over layer) layer {
+	values, o := mergeMaps(base.values, over.values, base.origin, over.origin, "")
+	return layer{values: values, origin: o}
+}
+
+// mergeMaps overlays over onto base: maps are merged recursively, lists are
+// appended (skipping items already inherited, except in argumentLists), and
+// everything else, including commands, is replaced. Sentinels in over delete
+// or replace the inherited value. Neither argument is modified. The origins
+// bo and oo of base and over are merged alongside, so that every key of the
+// result keeps the position it was written at.
+func mergeMaps(base, over map[string]any, bo, oo *origin, path string) (map[string]any, *origin) {
+	out := make(map[string]any, len(base)+len(over))
+	for k, v := range base {
+		out[k] = v
+	}
+	org := &origin{kind: yaml.MappingNode, keys: map[string]*origin{}}
+	if oo != nil {
+		org.at = oo.at
+	}
+	if bo != nil {
+		for k, c := range bo.keys {
+			org.keys[k] = c
+		}
+	}
+	for k, v := range over {
+		p := joinField(path, k)
+		if v == deleteSentinel {
+			delete(out, k)
+			delete(org.keys, k)
+			continue
+		}
+		if r, ok := replaced(v); ok {
+			out[k] = clean(r)
+			org.keys[k] = oo.key(k)
+			continue
+		}
+		out[k], org.keys[k] = mergeValue(out[k], v, org.keys[k], oo.key(k), p)
+	}
+	return out, org
+}
+
+func mergeValue(base, over any, bo, oo *origin, path string) (any, *origin) {
+	switch o := over.(type) {
+	case map[string]any:
+		if b, ok := base.(map[string]any); ok {
+			return mergeMaps(b, o, bo, oo, path)
+		}
+	case []any:
+		if b, ok := base.([]any); ok && !commandFields[splitPath(path)[0]] {
+			out := append([]any{}, b...)
+			org := &origin{kind: yaml.SequenceNode}
+			if oo != nil {
+				org.at = oo.at
+			}
+			for i := range b {
+				org.items = append(org.items, bo.item(i))
+			}
+			for i, item := range o {
+				item = clean(item)
+				if !argumentLists[path] && containsValue(out, item) {
+					continue
+				}
+				out = append(out, item)
+				org.items = append(org.items, oo.item(i))
+			}
+			return out, org
+		}
+	}
+	return clean(over), oo
+}
+
+// replaced unwraps a value marked with ReplaceTag.
+func replaced(v any) (any, bool) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return nil, false
+	}
+	r, ok := m[replaceKey]
+	return r, ok
+}
+
+// clean strips sentinels from a value that has nothing left to merge with:
+// deletions of keys that were never inherited and replace wrappers.
+func clean(v any) any {
+	if r, ok := replaced(v); ok {
+		return clean(r)
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			if item == deleteSentinel {
+				continue
+			}
+			out[k] = clean(item)
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			if item == deleteSentinel {
+				continue
+			}
+			out = append(out, clean(item))
+		}
+		return out
+	}
+	return v
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// mapProvider is a koanf.Provider serving an already-merged config map.
+type mapProvider map[string]any
+
+func (m mapProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("mapProvider does not support ReadBytes")
+}
+
+func (m mapProvider) Read() (map[string]any, error) {
+	return m, nil
+}
@@ -0,0 +1,275 @@
+package devcontainer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadYAMLFileExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base/root.yaml": `name: root
+image: ubuntu:22.04
+remoteUser: vscode
+containerEnv:
+  TZ: UTC
+  DEBUG: "1"
+forwardPorts: [3000]
+runArgs: ["--cap-add", "SYS_PTRACE"]
+postCreateCommand: ["npm", "ci"]
+`,
+		"base/company.yaml": `extends: root.yaml
+features:
+  ghcr.io/devcontainers/features/go:1: {version: "1.22"}
+customizations:
+  vscode:
+    extensions: [golang.go]
+    settings:
+      editor.formatOnSave: true
+`,
+		"repo/config.yaml": `extends: ../base/company.yaml
+name: repo
+remoteUser: !delete
+containerEnv:
+  DEBUG: !delete
+  APP: repo
+forwardPorts: [3000, 8080]
+runArgs: ["--cap-add", "NET_RAW"]
+postCreateCommand: ["make", "setup"]
+features:
+  ghcr.io/devcontainers/features/go:1: {version: "1.23"}
+customizations:
+  vscode:
+    extensions: !replace [golang.go, ms-azuretools.vscode-docker]
+    settings:
+      go.lintTool: golangci-lint
+`,
+	})
+
+	k, err := LoadYAMLFile(filepath.Join(dir, "repo", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatalf("merged config does not parse: %v", err)
+	}
+
+	if dc.Name != "repo" || dc.Image != "ubuntu:22.04" || dc.RemoteUser != "" {
+		t.Errorf("scalars: name=%q image=%q remoteUser=%q", dc.Name, dc.Image, dc.RemoteUser)
+	}
+	if want := map[string]string{"TZ": "UTC", "APP": "repo"}; !reflect.DeepEqual(dc.ContainerEnv, want) {
+		t.Errorf("containerEnv = %v, want %v", dc.ContainerEnv, want)
+	}
+	if want := []model.PortSpec{model.PortNumber(3000), model.PortNumber(8080)}; !reflect.DeepEqual(dc.ForwardPorts, want) {
+		t.Errorf("forwardPorts = %#v, want %#v", dc.ForwardPorts, want)
+	}
+	if want := []string{"--cap-add", "SYS_PTRACE", "--cap-add", "NET_RAW"}; !reflect.DeepEqual(dc.RunArgs, want) {
+		t.Errorf("runArgs = %v, want %v", dc.RunArgs, want)
+	}
+	if got := dc.PostCreateCommand.Items; !reflect.DeepEqual(got, []string{"make", "setup"}) {
+		t.Errorf("postCreateCommand = %v, want the overriding command", got)
+	}
+	if got := dc.Features["ghcr.io/devcontainers/features/go:1"].Map()["version"]; got != "1.23" {
+		t.Errorf("feature version = %v, want 1.23", got)
+	}
+	vs := dc.Customizations.VSCode
+	if want := []string{"golang.go", "ms-azuretools.vscode-docker"}; !reflect.DeepEqual(vs.Extensions, want) {
+		t.Errorf("extensions = %v, want %v", vs.Extensions, want)
+	}
+	if len(vs.Settings) != 2 {
+		t.Errorf("settings = %v, want both inherited and own keys", vs.Settings)
+	}
+}
+
+func TestLoadYAMLFileExtendsCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.yaml": "extends: b.yaml\nname: a\n",
+		"b.yaml": "extends: [c.yaml]\n",
+		"c.yaml": "extends: a.yaml\n",
+	})
+	_, err := LoadYAMLFile(filepath.Join(dir, "a.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "extends cycle: a.yaml -> b.yaml -> c.yaml -> a.yaml") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestLoadYAMLFileExtendsMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.yaml": "extends: nope.yaml\nname: a\n"})
+	_, err := LoadYAMLFile(filepath.Join(dir, "a.yaml"))
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestLoadYAMLFileProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.yaml": `image: ubuntu:22.04
+profiles:
+  ci:
+    runArgs: ["--network=host"]
+`,
+		"config.yaml": `extends: base.yaml
+name: t
+runArgs: ["--init"]
+mounts:
+  - type=bind,source=/a,target=/a
+profiles:
+  ci:
+    remoteUser: ci
+  codespaces:
+    mounts: !delete
+    hostRequirements:
+      cpus: 4
+`,
+	})
+	path := filepath.Join(dir, "config.yaml")
+
+	k, err := LoadYAMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatalf("profiles section must not reach Parse: %v", err)
+	}
+	if len(dc.Mounts) != 1 || dc.HostRequirements != nil {
+		t.Errorf("profiles applied without being selected: %+v", dc)
+	}
+
+	k, err = LoadYAMLFile(path, "ci", "codespaces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dc, err = ParseStrict(k); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"--init", "--network=host"}; !reflect.DeepEqual(dc.RunArgs, want) {
+		t.Errorf("runArgs = %v, want %v (inherited ci profile)", dc.RunArgs, want)
+	}
+	if dc.RemoteUser != "ci" || len(dc.Mounts) != 0 || dc.HostRequirements == nil || dc.HostRequirements.CPUs != 4 {
+		t.Errorf("profiles not applied: remoteUser=%q mounts=%v hostRequirements=%+v", dc.RemoteUser, dc.Mounts, dc.HostRequirements)
+	}
+
+	if _, err := LoadYAMLFile(path, "local"); err == nil || !strings.Contains(err.Error(), "unknown profile 'local' (available: ci, codespaces)") {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestLoadPositionsExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base/base.yaml": `image: ubuntu:22.04
+mounts:
+  - type: volume
+    source: cache
+profiles:
+  ci:
+    remoteUser: ci
+`,
+		"config.yaml": `extends: base/base.yaml
+name: t
+mounts:
+  - type: bind
+    source: /v
+    target: /v
+`,
+	})
+	path := filepath.Join(dir, "config.yaml")
+	base := filepath.Join(dir, "base", "base.yaml")
+
+	k, err := LoadYAMLFile(path, "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos, err := LoadPositions(path, "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := ValidationDiagnostics(Validate(dc), "config.yaml", pos)
+	if len(ds) != 1 {
+		t.Fatalf("expected one diagnostic, got %+v", ds)
+	}
+	if d := ds[0]; d.Path != "mounts[0].target" || d.File != base || d.Line != 3 || d.Column != 5 {
+		t.Errorf("missing target reported at %s:%d:%d (%s), want %s:3:5", d.File, d.Line, d.Column, d.Path, base)
+	}
+
+	for path, want := range map[string]Position{
+		"image":            {File: base, Line: 1, Column: 1},
+		"name":             {Line: 2, Column: 1},
+		"mounts[1].target": {Line: 6, Column: 5},
+		"remoteUser":       {File: base, Line: 7, Column: 5},
+	} {
+		if got := pos[path]; got != want {
+			t.Errorf("%s: got %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestLoadConfigRebasesBasePaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"shared/base.yaml": `build:
+  dockerfile: Dockerfile
+  context: .
+dockerComposeFile: [compose.yml, /abs/compose.yml]
+profiles:
+  ci:
+    dockerFile: ci/Dockerfile
+`,
+		"repo/config.yaml": "extends: ../shared/base.yaml\nname: t\n",
+	})
+	path := filepath.Join(dir, "repo", "config.yaml")
+
+	k, err := LoadConfig(path, LoadOptions{Profiles: []string{"ci"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := k.String("build.dockerfile"); got != "../shared/Dockerfile" {
+		t.Errorf("build.dockerfile = %q, want ../shared/Dockerfile", got)
+	}
+	if got := k.String("build.context"); got != "../shared" {
+		t.Errorf("build.context = %q, want ../shared", got)
+	}
+	if got := k.String("dockerFile"); got != "../shared/ci/Dockerfile" {
+		t.Errorf("dockerFile = %q, want ../shared/ci/Dockerfile", got)
+	}
+	if got, want := k.Strings("dockerComposeFile"), []string{"../shared/compose.yml", "/abs/compose.yml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dockerComposeFile = %v, want %v", got, want)
+	}
+
+	k, err = LoadConfig(path, LoadOptions{PathsAsWritten: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := k.String("build.dockerfile"); got != "Dockerfile" {
+		t.Errorf("with PathsAsWritten build.dockerfile = %q, want Dockerfile", got)
+	}
+}
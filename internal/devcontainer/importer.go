@@ -0,0 +1,152 @@
+package devcontainer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// ImportFile reads a devcontainer.json (JSONC: comments and trailing commas
+// allowed) and returns the equivalent config.yaml. Top-level keys follow
+// model.TopLevelKeys and JSONC comments are carried over as YAML comments
+// wherever the entry they belong to survives the round trip.
+func ImportFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied input path
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return ImportJSONC(data)
+}
+
+// ImportJSONC converts JSONC devcontainer bytes into config.yaml bytes.
+func ImportJSONC(data []byte) ([]byte, error) {
+	clean, comments := StripJSONC(data)
+
+	var dc model.DevContainer
+	if err := json.Unmarshal(clean, &dc); err != nil {
+		return nil, fmt.Errorf("error decoding devcontainer.json: %w", err)
+	}
+	// Drop the $schema Marshal stamps on every file so config.yaml stays free
+	// of generated noise.
+	if dc.Schema == defaultSchema {
+		dc.Schema = ""
+	}
+
+	return EncodeConfigYAML(dc, comments)
+}
+
+// EncodeConfigYAML renders dc as config.yaml with top-level keys in
+// model.TopLevelKeys order. comments may be nil.
+func EncodeConfigYAML(dc model.DevContainer, comments Comments) ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(dc); err != nil {
+		return nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	sortTopLevel(&root)
+	if comments != nil {
+		if c, ok := comments[commentKey(nil)]; ok {
+			root.HeadComment = yamlComment(c.Head)
+		}
+		applyComments(&root, nil, comments)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sortTopLevel reorders the key/value pairs of a mapping node so keys listed in
+// model.TopLevelKeys come first, in that order. Anything else keeps its
+// relative position after them.
+func sortTopLevel(n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	rank := make(map[string]int, len(model.TopLevelKeys))
+	for i, k := range model.TopLevelKeys {
+		rank[k] = i
+	}
+	type pair struct{ k, v *yaml.Node }
+	pairs := make([]pair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, pair{n.Content[i], n.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		ri, iok := rank[pairs[i].k.Value]
+		rj, jok := rank[pairs[j].k.Value]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		}
+		return false
+	})
+	n.Content = n.Content[:0]
+	for _, p := range pairs {
+		n.Content = append(n.Content, p.k, p.v)
+	}
+}
+
+// applyComments walks n and attaches the comment recorded for each path.
+// Comments on a mapping entry whose value is a scalar trail the value; on
+// a block value they trail the key.
+func applyComments(n *yaml.Node, path []string, comments Comments) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			p := append(append([]string{}, path...), k.Value)
+			if c, ok := comments[commentKey(p)]; ok {
+				k.HeadComment = yamlComment(c.Head)
+				k.FootComment = yamlComment(c.Foot)
+				if v.Kind == yaml.ScalarNode {
+					v.LineComment = yamlComment(c.Line)
+				} else {
+					k.LineComment = yamlComment(c.Line)
+				}
+			}
+			applyComments(v, p, comments)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			p := append(append([]string{}, path...), "["+strconv.Itoa(i)+"]")
+			if c, ok := comments[commentKey(p)]; ok {
+				item.HeadComment = yamlComment(c.Head)
+				item.LineComment = yamlComment(c.Line)
+				item.FootComment = yamlComment(c.Foot)
+			}
+			applyComments(item, p, comments)
+		}
+	}
+}
+
+// yamlComment joins comment lines into the "# "-prefixed form yaml.v3 expects.
+func yamlComment(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	var b bytes.Buffer
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString("# ")
+		b.WriteString(l)
+	}
+	return b.String()
+}
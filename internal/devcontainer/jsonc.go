@@ -0,0 +1,256 @@
+package devcontainer
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Comments holds the JSONC comments found in a devcontainer.json, keyed by the
+// path of the entry they belong to (see commentKey). Importers use it to carry
+// comments over to the generated config.yaml.
+type Comments map[string]*EntryComments
+
+// EntryComments are the comments attached to a single object key or array item.
+type EntryComments struct {
+	Head []string // comment lines above the entry
+	Line []string // comment trailing the entry on the same line
+	Foot []string // comment lines after the last entry of a container
+}
+
+// commentKey joins path segments into a Comments key. Object keys are used as
+// is, array items as "[i]" and the empty path is the file header. The
+// separator cannot appear in JSON keys that humans write, so dotted feature
+// IDs and settings stay unambiguous.
+func commentKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// jsoncFrame is one open object or array while scanning.
+type jsoncFrame struct {
+	array     bool
+	expectKey bool
+	index     int
+	key       string
+	last      []string // path of the most recent entry in this container
+}
+
+// StripJSONC removes // and /* */ comments and trailing commas from a JSONC
+// document so encoding/json can decode it. Comments are returned alongside,
+// attached to the entry they precede or trail.
+func StripJSONC(data []byte) ([]byte, Comments) {
+	var (
+		out      bytes.Buffer
+		stack    []*jsoncFrame
+		pending  []string
+		comments = Comments{}
+
+		line          = 1
+		lastTokenLine = 0
+		lastEntry     []string
+	)
+
+	entry := func(path []string) *EntryComments {
+		k := commentKey(path)
+		c, ok := comments[k]
+		if !ok {
+			c = &EntryComments{}
+			comments[k] = c
+		}
+		return c
+	}
+	path := func() []string {
+		var p []string
+		for _, f := range stack {
+			if f.array {
+				p = append(p, "["+strconv.Itoa(f.index)+"]")
+			} else if f.key != "" {
+				p = append(p, f.key)
+			}
+		}
+		return p
+	}
+	// beginValue records the start of an array item so pending comments land
+	// on it. Object values were already claimed by their key.
+	beginValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if !top.array {
+			return
+		}
+		p := path()
+		if len(pending) > 0 {
+			entry(p).Head = append(entry(p).Head, pending...)
+			pending = nil
+		}
+		top.last = p
+		lastEntry = p
+	}
+	addComment := func(text string, startLine int) {
+		text = strings.TrimSpace(text)
+		if lastEntry != nil && startLine == lastTokenLine {
+			e := entry(lastEntry)
+			e.Line = append(e.Line, text)
+			return
+		}
+		pending = append(pending, text)
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\n':
+			line++
+			out.WriteByte(c)
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			end := bytes.IndexByte(data[i:], '\n')
+			if end < 0 {
+				end = len(data) - i
+			}
+			addComment(string(data[i+2:i+end]), line)
+			i += end - 1
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			start := line
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(data) - i - 2
+			}
+			body := data[i+2 : i+2+end]
+			for _, l := range strings.Split(string(body), "\n") {
+				if l = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l), "*")); l != "" {
+					addComment(l, start)
+				}
+			}
+			// Keep line numbering intact for everything after the comment.
+			n := bytes.Count(body, []byte("\n"))
+			line += n
+			out.Write(bytes.Repeat([]byte("\n"), n))
+			i += end + 3
+
+		case c == '"':
+			end := i + 1
+			for end < len(data) && data[end] != '"' {
+				if data[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(data) {
+				end = len(data) - 1
+			}
+			lit := data[i : end+1]
+			out.Write(lit)
+			i = end
+			lastTokenLine = line
+
+			if len(stack) > 0 && !stack[len(stack)-1].array && stack[len(stack)-1].expectKey {
+				top := stack[len(stack)-1]
+				key, err := strconv.Unquote(string(lit))
+				if err != nil {
+					key = string(lit[1 : len(lit)-1])
+				}
+				top.key = key
+				top.expectKey = false
+				p := path()
+				if len(pending) > 0 {
+					entry(p).Head = append(entry(p).Head, pending...)
+					pending = nil
+				}
+				top.last = p
+				lastEntry = p
+				continue
+			}
+			beginValue()
+
+		case c == ',':
+			// Drop trailing commas: a comma whose next significant byte closes
+			// the container.
+			if next := nextSignificant(data, i+1); next == '}' || next == ']' {
+				continue
+			}
+			out.WriteByte(c)
+			lastTokenLine = line
+			if len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.array {
+					top.index++
+				} else {
+					top.expectKey = true
+				}
+			}
+
+		case c == '{' || c == '[':
+			if len(stack) == 0 && len(pending) > 0 {
+				// Comments above the root object describe the whole file.
+				entry(nil).Head = append(entry(nil).Head, pending...)
+				pending = nil
+			}
+			beginValue()
+			out.WriteByte(c)
+			lastTokenLine = line
+			stack = append(stack, &jsoncFrame{array: c == '[', expectKey: c == '{'})
+
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if len(pending) > 0 && top.last != nil {
+					e := entry(top.last)
+					e.Foot = append(e.Foot, pending...)
+					pending = nil
+				}
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 {
+					lastEntry = stack[len(stack)-1].last
+				}
+			}
+			out.WriteByte(c)
+			lastTokenLine = line
+
+		case c == ' ' || c == '\t' || c == '\r' || c == ':':
+			out.WriteByte(c)
+
+		default:
+			// Bare scalar: number, true, false or null.
+			end := i
+			for end < len(data) && !bytes.ContainsRune([]byte(",]} \t\r\n/"), rune(data[end])) {
+				end++
+			}
+			beginValue()
+			out.Write(data[i:end])
+			lastTokenLine = line
+			i = end - 1
+		}
+	}
+
+	return out.Bytes(), comments
+}
+
+// nextSignificant returns the next byte at or after i that is neither
+// whitespace nor part of a comment, or 0 at end of input.
+func nextSignificant(data []byte, i int) byte {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n':
+			i++
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '/':
+			end := bytes.IndexByte(data[i:], '\n')
+			if end < 0 {
+				return 0
+			}
+			i += end
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0
+			}
+			i += end + 4
+		default:
+			return data[i]
+		}
+	}
+	return 0
+}
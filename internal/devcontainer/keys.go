@@ -0,0 +1,243 @@
+package devcontainer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	koanf "github.com/knadh/koanf/v2"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// UnknownKey is a config key that does not map to any DevContainer field.
+type UnknownKey struct {
+	Path       string // full YAML path of the key, e.g. build.dockerfil
+	Suggestion string // closest valid path, empty when nothing is close
+}
+
+func (u UnknownKey) String() string {
+	if u.Suggestion != "" {
+		return fmt.Sprintf("Unknown key '%s' (did you mean '%s'?)", u.Path, u.Suggestion)
+	}
+	return fmt.Sprintf("Unknown key '%s'.", u.Path)
+}
+
+// UnknownKeysError is returned by ParseStrict when the config contains keys
+// that mapstructure would otherwise drop silently.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	lines := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		lines[i] = k.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseStrict is Parse, but fails with an *UnknownKeysError when k contains
+// keys that no DevContainer field accepts.
+func ParseStrict(k *koanf.Koanf) (model.DevContainer, error) {
+	if unknown := UnknownKeys(k); len(unknown) > 0 {
+		return model.DevContainer{}, &UnknownKeysError{Keys: unknown}
+	}
+	return Parse(k)
+}
+
+// UnknownKeys reports every key in k that does not correspond to a
+// DevContainer field, walking nested structs, map values and list items.
+// Free-form maps (settings, feature options, env) accept any key, as do the
+// passthrough keys: top-level x-* keys and unknown customization tools.
+func UnknownKeys(k *koanf.Koanf) []UnknownKey {
+	var out []UnknownKey
+	walkUnknown(devContainerType, k.Raw(), "", &out)
+	return out
+}
+
+// unionShapes maps each union wrapper type to the struct its mapping form
+// decodes into. A nil entry means the mapping form has free-form keys, or
+// that the type has no mapping form.
+var unionShapes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(model.CommandValue{}):   nil,
+	reflect.TypeOf(model.GPUValue{}):       reflect.TypeOf(model.GPURequirement{}),
+	reflect.TypeOf(model.MountOrString{}):  reflect.TypeOf(model.Mount{}),
+	reflect.TypeOf(model.PortSpec{}):       nil,
+	reflect.TypeOf(model.StringOrSlice{}):  nil,
+	reflect.TypeOf(model.FeatureOptions{}): nil,
+	reflect.TypeOf(model.Permissions{}):    nil,
+}
+
+func walkUnknown(t reflect.Type, v any, path string, out *[]UnknownKey) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if shape, ok := unionShapes[t]; ok {
+		if shape == nil {
+			return
+		}
+		t = shape
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := yamlFields(t)
+		for _, key := range SortedKeys(m) {
+			p := joinField(path, key)
+			f, ok := fields[key]
+			if !ok {
+				if isPassthroughKey(t, key) {
+					continue
+				}
+				*out = append(*out, UnknownKey{Path: p, Suggestion: suggestKey(t, path, key)})
+				continue
+			}
+			walkUnknown(f.Type, m[key], p, out)
+		}
+	case reflect.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, key := range SortedKeys(m) {
+			walkUnknown(t.Elem(), m[key], joinMapKey(path, key), out)
+		}
+	case reflect.Slice:
+		items, ok := v.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			walkUnknown(t.Elem(), item, joinIndex(path, i), out)
+		}
+	}
+}
+
+// yamlFields indexes the exported fields of struct type t by YAML name.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := yamlName(t.Field(i)); name != "" {
+			fields[name] = t.Field(i)
+		}
+	}
+	return fields
+}
+
+// yamlName returns the YAML key of f, or "" when f is unexported or hidden.
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if !f.IsExported() || name == "-" {
+		return ""
+	}
+	return name
+}
+
+// suggestKey returns the valid path closest to key. Siblings in the same
+// struct are preferred; failing that, any struct field in the model with a
+// near-identical name is offered (so a stray top-level "extensions" points at
+// customizations.vscode.extensions).
+func suggestKey(t reflect.Type, parent, key string) string {
+	best, bestDist := "", maxSuggestDistance(key)+1
+	for name := range yamlFields(t) {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	if best != "" {
+		return joinField(parent, best)
+	}
+
+	for _, p := range knownFieldPaths() {
+		leaf := p[strings.LastIndex(p, ".")+1:]
+		if editDistance(strings.ToLower(key), strings.ToLower(leaf)) <= 1 {
+			return p
+		}
+	}
+	return ""
+}
+
+// maxSuggestDistance is the largest edit distance still worth suggesting:
+// roughly one typo per four characters, at least two.
+func maxSuggestDistance(key string) int {
+	return max(2, len(key)/4)
+}
+
+// closest returns the candidate nearest to name within maxSuggestDistance,
+// or "" when none is close. Ties go to the earliest candidate.
+func closest(name string, candidates []string) string {
+	best, bestDist := "", maxSuggestDistance(name)+1
+	for _, c := range candidates {
+		if d := editDistance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// knownFieldPaths lists every path reachable through struct fields alone
+// (build.dockerfile, customizations.vscode.extensions, …) in declaration
+// order, so the first match is the most common home for a key.
+func knownFieldPaths() []string {
+	var paths []string
+	var walk func(t reflect.Type, prefix string, depth int)
+	walk = func(t reflect.Type, prefix string, depth int) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || depth > 3 {
+			return
+		}
+		if _, union := unionShapes[t]; union {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			name := yamlName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			p := joinField(prefix, name)
+			paths = append(paths, p)
+			walk(t.Field(i).Type, p, depth+1)
+		}
+	}
+	walk(reflect.TypeOf(model.DevContainer{}), "", 0)
+	return paths
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// SortedKeys returns the keys of m in sorted order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,14 +3,37 @@ package devcontainer
 import (
 	"fmt"
 
-	kyaml "github.com/knadh/koanf/parsers/yaml"
-	kfile "github.com/knadh/koanf/providers/file"
 	koanf "github.com/knadh/koanf/v2"
 )
 
-func LoadYAMLFile(path string) (*koanf.Koanf, error) {
+// LoadOptions adjusts how LoadConfig builds a config from its extends chain.
+type LoadOptions struct {
+	// Profiles are the profiles to merge on top of the config, in order.
+	Profiles []string
+	// PathsAsWritten keeps the relative paths of base files as written
+	// instead of rebasing them onto the folder of the config file.
+	PathsAsWritten bool
+}
+
+// LoadYAMLFile loads the config file at path with LoadConfig and the given
+// profiles.
+func LoadYAMLFile(path string, profiles ...string) (*koanf.Koanf, error) {
+	return LoadConfig(path, LoadOptions{Profiles: profiles})
+}
+
+// LoadConfig loads the config file at path. When it has an extends key, the
+// files it names are loaded first (recursively, relative to the file that
+// names them) and the config is deep-merged on top of them. The relative
+// paths RebasePaths handles are rebased from each base file's folder onto the
+// folder of path first, unless opts.PathsAsWritten is set. The overlays of
+// opts.Profiles are then merged on top, in order.
+func LoadConfig(path string, opts LoadOptions) (*koanf.Koanf, error) {
+	m, _, err := loadConfigTree(path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error loading file: %w", err)
+	}
 	k := koanf.New(".")
-	if err := k.Load(kfile.Provider(path), kyaml.Parser()); err != nil {
+	if err := k.Load(mapProvider(m), nil); err != nil {
 		return nil, fmt.Errorf("error loading file: %w", err)
 	}
 	return k, nil
@@ -0,0 +1,335 @@
+package devcontainer
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// Migration is one rewrite made by Migrate, or one it had to leave out.
+type Migration struct {
+	Pos     Position // where the migrated key or list item was in the input
+	Message string
+	Skipped bool // the entry was left as written
+}
+
+// Migrate rewrites the deprecated fields of a config.yaml, and of each of its
+// profiles, to their current form:
+//
+//   - dockerFile becomes build.dockerfile, with the legacy top-level context
+//     moved to build.context or "." (the folder of devcontainer.json, as before)
+//   - appPort entries become forwardPorts, with portsAttributes that keep the
+//     local port fixed and the forwarding silent, as publishing did
+//   - --mount strings become mount objects
+//
+// Entries the current form cannot express are kept and reported as skipped.
+// The document is edited as a yaml.Node tree, so comments survive but blank
+// lines do not, and indentation becomes two spaces; data is returned
+// unchanged when nothing was rewritten. Files named in extends are not
+// followed.
+func Migrate(data []byte) ([]byte, []Migration, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("error loading file: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil, nil
+	}
+	root := doc.Content[0]
+
+	var out []Migration
+	migrateConfig(root, "", &out)
+	if i := mappingIndex(root, "profiles"); i >= 0 && root.Content[i+1].Kind == yaml.MappingNode {
+		profiles := root.Content[i+1]
+		for j := 0; j+1 < len(profiles.Content); j += 2 {
+			if p := profiles.Content[j+1]; p.Kind == yaml.MappingNode {
+				migrateConfig(p, joinField("profiles", profiles.Content[j].Value), &out)
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].Pos, out[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	changed := false
+	for _, m := range out {
+		changed = changed || !m.Skipped
+	}
+	if !changed {
+		return data, out, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	return buf.Bytes(), out, nil
+}
+
+func migrateConfig(m *yaml.Node, path string, out *[]Migration) {
+	migrateDockerFile(m, path, out)
+	migrateAppPort(m, path, out)
+	migrateMounts(m, path, out)
+}
+
+func migrateDockerFile(m *yaml.Node, path string, out *[]Migration) {
+	i := mappingIndex(m, "dockerFile")
+	if i < 0 || !plainNode(m.Content[i+1]) {
+		return
+	}
+	key, dockerfile := m.Content[i], m.Content[i+1]
+	at := Position{Line: key.Line, Column: key.Column}
+	from := joinField(path, "dockerFile")
+
+	if j := mappingIndex(m, "build"); j >= 0 {
+		build := m.Content[j+1]
+		if build.Kind != yaml.MappingNode || mappingIndex(build, "dockerfile") >= 0 {
+			*out = append(*out, Migration{Pos: at, Skipped: true,
+				Message: fmt.Sprintf("Kept '%s': '%s' already names a Dockerfile.", from, joinField(path, "build.dockerfile"))})
+			return
+		}
+		build.Content = append(build.Content, yamlKey("dockerfile"), dockerfile)
+		removePair(m, i)
+		*out = append(*out, Migration{Pos: at,
+			Message: fmt.Sprintf("Moved '%s' to '%s'.", from, joinField(path, "build.dockerfile"))})
+		return
+	}
+
+	context := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "."}
+	if j := mappingIndex(m, "context"); j >= 0 && m.Content[j+1].Kind == yaml.ScalarNode {
+		context = m.Content[j+1]
+		removePair(m, j)
+		if j < i {
+			i -= 2
+		}
+	}
+	key.Value = "build"
+	m.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map",
+		Content: []*yaml.Node{yamlKey("dockerfile"), dockerfile, yamlKey("context"), context}}
+	*out = append(*out, Migration{Pos: at,
+		Message: fmt.Sprintf("Replaced '%s' with '%s' and context '%s'.", from, joinField(path, "build.dockerfile"), context.Value)})
+}
+
+func migrateAppPort(m *yaml.Node, path string, out *[]Migration) {
+	i := mappingIndex(m, "appPort")
+	if i < 0 || !plainNode(m.Content[i+1]) {
+		return
+	}
+	key, value := m.Content[i], m.Content[i+1]
+	items := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		items = value.Content
+	}
+
+	var (
+		ports []int
+		kept  []*yaml.Node
+	)
+	for n, item := range items {
+		p := joinField(path, "appPort")
+		if value.Kind == yaml.SequenceNode {
+			p = joinIndex(p, n)
+		}
+		port, err := forwardablePort(item)
+		if err != nil {
+			kept = append(kept, item)
+			*out = append(*out, Migration{Pos: Position{Line: item.Line, Column: item.Column}, Skipped: true,
+				Message: fmt.Sprintf("Kept '%s': %v.", p, err)})
+			continue
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) == 0 {
+		return
+	}
+	if reason := portTargetsReason(m, path); reason != "" {
+		*out = append(*out, Migration{Pos: Position{Line: key.Line, Column: key.Column}, Skipped: true,
+			Message: fmt.Sprintf("Kept '%s': %s.", joinField(path, "appPort"), reason)})
+		return
+	}
+
+	forward := sequenceValue(m, i, "forwardPorts")
+	attrs := mappingValue(m, mappingIndex(m, "forwardPorts"), "portsAttributes")
+	for _, port := range ports {
+		s := strconv.Itoa(port)
+		if !containsScalar(forward.Content, s) {
+			forward.Content = append(forward.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: s})
+		}
+		if mappingIndex(attrs, s) < 0 {
+			attrs.Content = append(attrs.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s, Style: yaml.DoubleQuotedStyle},
+				&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+					yamlKey("onAutoForward"), {Kind: yaml.ScalarNode, Tag: "!!str", Value: "silent"},
+					yamlKey("requireLocalPort"), {Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+				}})
+		}
+	}
+
+	i = mappingIndex(m, "appPort")
+	if len(kept) == 0 {
+		removePair(m, i)
+	} else {
+		m.Content[i+1] = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: value.Style, Content: kept}
+	}
+	*out = append(*out, Migration{Pos: Position{Line: key.Line, Column: key.Column},
+		Message: fmt.Sprintf("Moved %s from '%s' to '%s' and '%s'.", plural(len(ports), "port"),
+			joinField(path, "appPort"), joinField(path, "forwardPorts"), joinField(path, "portsAttributes"))})
+}
+
+// portTargetsReason explains why appPort cannot be moved into m, or returns
+// "" when it can: an existing forwardPorts must be a list and an existing
+// portsAttributes a mapping, or adding to them would corrupt the file.
+func portTargetsReason(m *yaml.Node, path string) string {
+	if j := mappingIndex(m, "forwardPorts"); j >= 0 && m.Content[j+1].Kind != yaml.SequenceNode {
+		return fmt.Sprintf("'%s' is not a list", joinField(path, "forwardPorts"))
+	}
+	if j := mappingIndex(m, "portsAttributes"); j >= 0 && m.Content[j+1].Kind != yaml.MappingNode {
+		return fmt.Sprintf("'%s' is not a mapping", joinField(path, "portsAttributes"))
+	}
+	return ""
+}
+
+// forwardablePort returns the container port of an appPort entry that
+// forwardPorts can stand in for: one published on the same local port over
+// TCP.
+func forwardablePort(n *yaml.Node) (int, error) {
+	if n.Kind != yaml.ScalarNode {
+		return 0, fmt.Errorf("not a port")
+	}
+	p := model.PortString(n.Value)
+	port, err := p.PublishedPort()
+	if err != nil {
+		return 0, err
+	}
+	spec, proto, _ := strings.Cut(n.Value, "/")
+	if proto == "udp" {
+		return 0, fmt.Errorf("forwardPorts cannot forward udp ports")
+	}
+	if parts := strings.Split(spec, ":"); len(parts) > 1 {
+		if host := parts[len(parts)-2]; host != "" && host != strconv.Itoa(port) {
+			return 0, fmt.Errorf("forwardPorts cannot publish container port %d on local port %s", port, host)
+		}
+	}
+	return port, nil
+}
+
+func migrateMounts(m *yaml.Node, path string, out *[]Migration) {
+	i := mappingIndex(m, "mounts")
+	if i < 0 || m.Content[i+1].Kind != yaml.SequenceNode || !plainNode(m.Content[i+1]) {
+		return
+	}
+	seq := m.Content[i+1]
+	for n, item := range seq.Content {
+		if item.Kind != yaml.ScalarNode || !plainNode(item) {
+			continue
+		}
+		at := Position{Line: item.Line, Column: item.Column}
+		p := joinIndex(joinField(path, "mounts"), n)
+		spec, err := model.ParseMountString(item.Value)
+		switch {
+		case err != nil:
+			*out = append(*out, Migration{Pos: at, Skipped: true, Message: fmt.Sprintf("Kept '%s': %v.", p, err)})
+			continue
+		case len(spec.Options) > 0:
+			*out = append(*out, Migration{Pos: at, Skipped: true,
+				Message: fmt.Sprintf("Kept '%s' as a string: the object form cannot express the '%s' option.", p, spec.Options[0].Key)})
+			continue
+		}
+		var obj yaml.Node
+		if err := obj.Encode(spec.Mount); err != nil {
+			continue
+		}
+		obj.HeadComment, obj.FootComment = item.HeadComment, item.FootComment
+		// A line comment on the mapping itself would be written after its last
+		// key, so keep it on the first line of the entry instead.
+		if len(obj.Content) >= 2 {
+			obj.Content[1].LineComment = item.LineComment
+		}
+		seq.Content[n] = &obj
+		seq.Style = 0
+		*out = append(*out, Migration{Pos: at, Message: fmt.Sprintf("Rewrote '%s' as a mount object.", p)})
+	}
+}
+
+// mappingIndex returns the index of key's key node in mapping m, or -1.
+func mappingIndex(m *yaml.Node, key string) int {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// sequenceValue returns the sequence under key in m, inserting an empty one
+// at index at when the key is missing. The caller checks that an existing
+// value is a sequence.
+func sequenceValue(m *yaml.Node, at int, key string) *yaml.Node {
+	if i := mappingIndex(m, key); i >= 0 {
+		return m.Content[i+1]
+	}
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	insertPair(m, at, yamlKey(key), seq)
+	return seq
+}
+
+// mappingValue returns the mapping under key in m, inserting an empty one
+// after the pair at index after when the key is missing. The caller checks
+// that an existing value is a mapping.
+func mappingValue(m *yaml.Node, after int, key string) *yaml.Node {
+	if i := mappingIndex(m, key); i >= 0 {
+		return m.Content[i+1]
+	}
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	insertPair(m, after+2, yamlKey(key), mapping)
+	return mapping
+}
+
+func insertPair(m *yaml.Node, at int, k, v *yaml.Node) {
+	m.Content = append(m.Content[:at], append([]*yaml.Node{k, v}, m.Content[at:]...)...)
+}
+
+func removePair(m *yaml.Node, i int) {
+	m.Content = append(m.Content[:i], m.Content[i+2:]...)
+}
+
+func yamlKey(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+// plainNode reports whether n carries no extends tag: a value marked
+// !delete or !replace is an instruction to the merge, not a field to migrate.
+func plainNode(n *yaml.Node) bool {
+	return n.Tag != DeleteTag && n.Tag != ReplaceTag
+}
+
+func containsScalar(nodes []*yaml.Node, s string) bool {
+	for _, n := range nodes {
+		if n.Kind == yaml.ScalarNode && n.Value == s {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,154 @@
+package devcontainer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	in := `# header
+name: legacy
+dockerFile: Dockerfile # relative to .devcontainer
+appPort: [3000, "8080:80"]
+mounts:
+  - source=/a,target=/a,type=bind,readonly
+  - type=volume,source=v,target=/v,volume-nocopy
+profiles:
+  ci:
+    dockerFile: !delete
+`
+	out, ms, err := Migrate([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# header
+name: legacy
+build:
+  dockerfile: Dockerfile # relative to .devcontainer
+  context: .
+forwardPorts:
+  - 3000
+portsAttributes:
+  "3000":
+    onAutoForward: silent
+    requireLocalPort: true
+appPort: ["8080:80"]
+mounts:
+  - type: bind
+    source: /a
+    target: /a
+    readonly: true
+  - type=volume,source=v,target=/v,volume-nocopy
+profiles:
+  ci:
+    dockerFile: !delete
+`
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+
+	var got []string
+	for _, m := range ms {
+		got = append(got, m.Pos.String()+" "+strings.Fields(m.Message)[0])
+	}
+	if want := []string{"3:1 Replaced", "4:1 Moved", "4:17 Kept", "6:5 Rewrote", "7:5 Kept"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("migrations = %v, want %v", got, want)
+	}
+	if !ms[2].Skipped || ms[1].Skipped {
+		t.Errorf("skipped flags wrong: %+v", ms)
+	}
+
+	again, ms, err := Migrate(out)
+	if err != nil || string(again) != string(out) || len(ms) != 2 {
+		t.Errorf("second run changed the file or found more to do: %v %+v", err, ms)
+	}
+}
+
+func TestMigrateDockerFileIntoBuild(t *testing.T) {
+	out, ms, err := Migrate([]byte("name: t\nbuild:\n  context: ..\ndockerFile: Dockerfile\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "name: t\nbuild:\n  context: ..\n  dockerfile: Dockerfile\n"; string(out) != want || len(ms) != 1 {
+		t.Errorf("got %q (%+v), want %q", out, ms, want)
+	}
+
+	in := "name: t\nbuild:\n  dockerfile: A\ndockerFile: B\n"
+	out, ms, err = Migrate([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in || len(ms) != 1 || !ms[0].Skipped {
+		t.Errorf("conflicting dockerFile must be kept: %q %+v", out, ms)
+	}
+}
+
+func TestMigrateMountKeepsLineComment(t *testing.T) {
+	in := `mounts:
+  - source=/a,target=/a,type=bind # cache
+  - source=/b,target=/b,type=bind
+`
+	out, _, err := Migrate([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `mounts:
+  - type: bind # cache
+    source: /a
+    target: /a
+  - type: bind
+    source: /b
+    target: /b
+`
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestMigrateAppPortKeepsMalformedTargets(t *testing.T) {
+	for _, in := range []string{
+		"appPort: [3000]\nforwardPorts: 8080\n",
+		"appPort: [3000]\nforwardPorts: {web: 8080}\n",
+		"appPort: [3000]\nportsAttributes: [3000]\n",
+	} {
+		out, ms, err := Migrate([]byte(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != in {
+			t.Errorf("%q was rewritten to:\n%s", in, out)
+		}
+		if len(ms) != 1 || !ms[0].Skipped || ms[0].Pos.Line != 1 {
+			t.Errorf("%q: migrations = %+v, want one skipped appPort", in, ms)
+		}
+	}
+}
+
+func TestMigrateDropsBlankLines(t *testing.T) {
+	in := `name: legacy
+
+dockerFile: Dockerfile
+
+appPort: [3000]
+`
+	out, _, err := Migrate([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The file is re-encoded from its node tree, which has no blank lines;
+	// migrate --help says so.
+	want := `name: legacy
+build:
+  dockerfile: Dockerfile
+  context: .
+forwardPorts:
+  - 3000
+portsAttributes:
+  "3000":
+    onAutoForward: silent
+    requireLocalPort: true
+`
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,50 @@
+package devcontainer
+
+import (
+	"fmt"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// MountFormatObject and MountFormatString are the forms NormalizeMounts can
+// write every mount in.
+const (
+	MountFormatObject = "object"
+	MountFormatString = "string"
+)
+
+// MountFormats lists the accepted values of convert --mount-format.
+var MountFormats = []string{MountFormatObject, MountFormatString}
+
+// RuleMountFormat identifies a mount NormalizeMounts could not write in the
+// requested form.
+const RuleMountFormat = "mount-format"
+
+// NormalizeMounts rewrites every mount of dc in format. MountFormatString
+// turns objects into canonical --mount strings and canonicalises existing
+// strings. MountFormatObject turns strings into objects, except those using
+// options the object form cannot express (consistency, volume-opt, ...): they
+// stay canonical strings and are reported as warnings. The mounts must have
+// passed validation.
+func NormalizeMounts(dc *model.DevContainer, format, file string, pos Positions) []diagnostic.Diagnostic {
+	var out []diagnostic.Diagnostic
+	for i, m := range dc.Mounts {
+		spec, err := m.Spec()
+		if err != nil {
+			continue
+		}
+		switch {
+		case format == MountFormatObject && len(spec.Options) == 0:
+			dc.Mounts[i] = model.MountObject(spec.Mount)
+		case format == MountFormatObject:
+			dc.Mounts[i] = model.MountString(spec.String())
+			path := joinIndex("mounts", i)
+			out = append(out, newDiagnostic(RuleMountFormat, diagnostic.SeverityWarning, file, pos, path,
+				fmt.Sprintf("Field '%s' uses the '%s' option, which the object form cannot express; keeping it as a string.", path, spec.Options[0].Key)))
+		default:
+			dc.Mounts[i] = model.MountString(spec.String())
+		}
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package devcontainer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestParseMountString(t *testing.T) {
+	spec, err := model.ParseMountString(`src=/a,dst=/b,type=bind,ro,consistency=cached,"bind-propagation=rshared"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.MountSpec{
+		Mount:   model.Mount{Type: "bind", Source: "/a", Target: "/b", ReadOnly: true},
+		Options: []model.MountOption{{Key: "consistency", Value: "cached"}, {Key: "bind-propagation", Value: "rshared"}},
+	}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("got %+v, want %+v", spec, want)
+	}
+	if got := spec.String(); got != "source=/a,target=/b,type=bind,readonly,consistency=cached,bind-propagation=rshared" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for s, msg := range map[string]string{
+		"source=/a,target=/b,type=bind,foo=1":                    "unknown mount option 'foo'",
+		"source=/a,target=/b,type=bind,consistency=x":            "invalid value 'x' for consistency",
+		"source=v,target=/b,type=volume,bind-propagation=shared": "cannot be used with a volume mount",
+		"source=/a,,target=/b":                                   "empty option",
+	} {
+		if _, err := model.ParseMountString(s); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("ParseMountString(%q) = %v, want error containing %q", s, err, msg)
+		}
+	}
+}
+
+func TestValidateMountStrings(t *testing.T) {
+	dc := model.DevContainer{
+		Name:  "t",
+		Image: "ubuntu",
+		Mounts: []model.MountOrString{
+			model.MountString("source=/a,target=/a,type=bind"),
+			model.MountString("source=/b,type=bind"),
+			model.MountString("source=/c,target=/c,type=nfs"),
+			model.MountString("source=/d,target=/d,type=bind,bogus"),
+		},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, d.RuleID+" "+d.Path)
+	}
+	want := []string{"required mounts[1].target", "oneof mounts[2].type", "mount mounts[3]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeMounts(t *testing.T) {
+	mounts := func() []model.MountOrString {
+		return []model.MountOrString{
+			model.MountString("type=bind,src=/a,dst=/a"),
+			model.MountString("source=/b,target=/b,type=bind,consistency=cached"),
+			model.MountObject(model.Mount{Type: "volume", Source: "v", Target: "/v", ReadOnly: true}),
+		}
+	}
+
+	dc := model.DevContainer{Mounts: mounts()}
+	ds := NormalizeMounts(&dc, MountFormatObject, "config.yaml", nil)
+	want := []model.MountOrString{
+		model.MountObject(model.Mount{Type: "bind", Source: "/a", Target: "/a"}),
+		model.MountString("source=/b,target=/b,type=bind,consistency=cached"),
+		model.MountObject(model.Mount{Type: "volume", Source: "v", Target: "/v", ReadOnly: true}),
+	}
+	if !reflect.DeepEqual(dc.Mounts, want) {
+		t.Errorf("object: got %+v", dc.Mounts)
+	}
+	if len(ds) != 1 || ds[0].Path != "mounts[1]" || ds[0].RuleID != RuleMountFormat {
+		t.Errorf("object: diagnostics = %v", ds)
+	}
+
+	dc = model.DevContainer{Mounts: mounts()}
+	if ds := NormalizeMounts(&dc, MountFormatString, "config.yaml", nil); len(ds) != 0 {
+		t.Errorf("string: diagnostics = %v", ds)
+	}
+	want = []model.MountOrString{
+		model.MountString("source=/a,target=/a,type=bind"),
+		model.MountString("source=/b,target=/b,type=bind,consistency=cached"),
+		model.MountString("source=v,target=/v,type=volume,readonly"),
+	}
+	if !reflect.DeepEqual(dc.Mounts, want) {
+		t.Errorf("string: got %+v", dc.Mounts)
+	}
+}
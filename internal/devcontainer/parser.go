@@ -3,6 +3,7 @@ package devcontainer
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 
 	mapstructure "github.com/go-viper/mapstructure/v2"
 	koanf "github.com/knadh/koanf/v2"
@@ -17,6 +18,10 @@ func Parse(k *koanf.Koanf) (model.DevContainer, error) {
 				commandValueDecodeHook,
 				gpuValueDecodeHook,
 				mountOrStringDecodeHook,
+				portSpecDecodeHook,
+				stringOrSliceDecodeHook,
+				featureOptionsDecodeHook,
+				permissionsDecodeHook,
 				mapstructure.StringToTimeDurationHookFunc(),
 				mapstructure.StringToSliceHookFunc(","),
 			),
@@ -26,6 +31,7 @@ func Parse(k *koanf.Koanf) (model.DevContainer, error) {
 	if err != nil {
 		return model.DevContainer{}, fmt.Errorf("error unmarshalling: %w", err)
 	}
+	collectExtra(&dc, k.Raw())
 	return dc, nil
 }
 
@@ -128,3 +134,109 @@ func mountOrStringDecodeHook(f, t reflect.Type, data any) (any, error) {
 	}
 	return data, nil
 }
+
+var portSpecType = reflect.TypeOf(model.PortSpec{})
+
+// portSpecDecodeHook converts number or string values into PortSpec.
+func portSpecDecodeHook(f, t reflect.Type, data any) (any, error) {
+	if t != portSpecType {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case int:
+		return model.PortNumber(v), nil
+	case int64:
+		return model.PortNumber(int(v)), nil
+	case float64:
+		if v != float64(int(v)) {
+			return nil, fmt.Errorf("port must be a whole number, got %v", v)
+		}
+		return model.PortNumber(int(v)), nil
+	case string:
+		return model.PortString(v), nil
+	}
+	return data, nil
+}
+
+var stringOrSliceType = reflect.TypeOf(model.StringOrSlice{})
+
+// stringOrSliceDecodeHook converts string or []any values into StringOrSlice,
+// remembering which form was used.
+func stringOrSliceDecodeHook(f, t reflect.Type, data any) (any, error) {
+	if t != stringOrSliceType {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		return model.SingleString(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected string at index %d, got %T", i, item)
+			}
+			items[i] = s
+		}
+		return model.StringSlice(items), nil
+	case []string:
+		return model.StringSlice(v), nil
+	}
+	return data, nil
+}
+
+var featureOptionsType = reflect.TypeOf(model.FeatureOptions{})
+
+// featureOptionsDecodeHook converts bool, version string, or map values into
+// FeatureOptions. An entry without a value installs the feature with its
+// default options, and a number is a version. readYAMLMap already reads
+// numbers in features as strings, so that 1.20 keeps its trailing zero.
+func featureOptionsDecodeHook(f, t reflect.Type, data any) (any, error) {
+	if t != featureOptionsType {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case nil:
+		return model.FeatureObject(nil), nil
+	case bool:
+		return model.FeatureBool(v), nil
+	case string:
+		return model.FeatureVersion(v), nil
+	case int:
+		return model.FeatureVersion(strconv.Itoa(v)), nil
+	case int64:
+		return model.FeatureVersion(strconv.FormatInt(v, 10)), nil
+	case uint64:
+		return model.FeatureVersion(strconv.FormatUint(v, 10)), nil
+	case float64:
+		return model.FeatureVersion(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case map[string]any:
+		return model.FeatureObject(v), nil
+	}
+	return data, nil
+}
+
+var permissionsType = reflect.TypeOf(model.Permissions{})
+
+// permissionsDecodeHook converts a shorthand string or a map of permission
+// names into Permissions.
+func permissionsDecodeHook(f, t reflect.Type, data any) (any, error) {
+	if t != permissionsType {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		return model.PermissionsAll(v), nil
+	case map[string]any:
+		scopes := make(map[string]string, len(v))
+		for k, val := range v {
+			s, ok := val.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected string value for permission %q, got %T", k, val)
+			}
+			scopes[k] = s
+		}
+		return model.PermissionScopes(scopes), nil
+	}
+	return data, nil
+}
@@ -0,0 +1,262 @@
+package devcontainer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestParseKeepsStringOrSliceForm(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	body := "name: t\ndockerComposeFile: compose,prod.yml\nservice: app\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadYAMLFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := Parse(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dc.DockerComposeFile.Values(); len(got) != 1 || got[0] != "compose,prod.yml" {
+		t.Fatalf("dockerComposeFile = %q, want a single unsplit path", got)
+	}
+	data, err := Marshal(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"dockerComposeFile": "compose,prod.yml"`) {
+		t.Errorf("scalar form not kept:\n%s", data)
+	}
+
+	body = "name: t\ndockerComposeFile:\n  - compose.yml\nservice: app\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if k, err = LoadYAMLFile(file); err != nil {
+		t.Fatal(err)
+	}
+	if dc, err = Parse(k); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = Marshal(dc); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\"dockerComposeFile\": [\n    \"compose.yml\"\n  ]") {
+		t.Errorf("array form not kept:\n%s", data)
+	}
+}
+
+func TestParseFeatureForms(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	body := "name: t\nimage: ubuntu\nfeatures:\n" +
+		"  ghcr.io/devcontainers/features/go:1: \"1.22\"\n" +
+		"  ghcr.io/devcontainers/features/git:1: true\n" +
+		"  ghcr.io/devcontainers/features/node:1:\n" +
+		"  ghcr.io/devcontainers/features/python:1:\n    version: \"3.12\"\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadYAMLFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := Parse(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(dc); err != nil {
+		t.Fatal(err)
+	}
+	if got := dc.Features["ghcr.io/devcontainers/features/go:1"].Map()["version"]; got != "1.22" {
+		t.Errorf("go version = %v, want 1.22", got)
+	}
+	data, err := Marshal(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"ghcr.io/devcontainers/features/go:1": "1.22"`,
+		`"ghcr.io/devcontainers/features/git:1": true`,
+		`"ghcr.io/devcontainers/features/node:1": {}`,
+		"\"ghcr.io/devcontainers/features/python:1\": {\n      \"version\": \"3.12\"\n    }",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %s\n%s", want, data)
+		}
+	}
+
+	dc.Features["ghcr.io/devcontainers/features/python:1"].Options["nested"] = map[string]any{"a": 1}
+	ds := ValidationDiagnostics(Validate(dc), "", nil)
+	if len(ds) != 1 || ds[0].Path != `features."ghcr.io/devcontainers/features/python:1"."nested"` {
+		t.Errorf("diagnostics = %v", ds)
+	}
+}
+
+func TestParsePassthroughKeys(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	body := "name: t\nimage: ubuntu\nx-team:\n  owner: infra\n" +
+		"customizations:\n  vscode:\n    extensions: [golang.go]\n  devpod:\n    prebuildRepository: ghcr.io/acme/prebuilds\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadYAMLFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatalf("passthrough keys must not be unknown: %v", err)
+	}
+	data, err := Marshal(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\"devpod\": {\n      \"prebuildRepository\": \"ghcr.io/acme/prebuilds\"\n    }",
+		"\"x-team\": {\n    \"owner\": \"infra\"\n  }\n}",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %s\n%s", want, data)
+		}
+	}
+
+	yml, err := ImportJSONC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"  devpod:\n    prebuildRepository: ghcr.io/acme/prebuilds\n", "x-team:\n  owner: infra\n"} {
+		if !strings.Contains(string(yml), want) {
+			t.Errorf("import lost %q\n%s", want, yml)
+		}
+	}
+
+	ds := PassthroughDiagnostics(&dc, file, nil)
+	if len(ds) != 2 || ds[0].Path != "x-team" || ds[1].Path != "customizations.devpod" {
+		t.Errorf("diagnostics = %v", ds)
+	}
+}
+
+func TestParseNullRemoteEnv(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	body := "name: t\nimage: ubuntu\nremoteEnv:\n  FOO: null\n  BAR: \"\"\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadYAMLFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := dc.RemoteEnv["FOO"]; !ok || v != nil {
+		t.Errorf("remoteEnv.FOO = %v, want a nil value", v)
+	}
+	if v := dc.RemoteEnv["BAR"]; v == nil || *v != "" {
+		t.Errorf("remoteEnv.BAR = %v, want an empty string", v)
+	}
+	data, err := Marshal(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"BAR": "",`) || !strings.Contains(string(data), `"FOO": null`) {
+		t.Errorf("null and empty values not kept apart:\n%s", data)
+	}
+}
+
+func TestParseCodespacesPermissions(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	body := "name: t\nimage: ubuntu\ncustomizations:\n  codespaces:\n    repositories:\n" +
+		"      my-org/*:\n        permissions: read-all\n" +
+		"      my-org/my-repo:\n        permissions:\n          contents: write\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadYAMLFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(dc); err != nil {
+		t.Fatalf("both permission forms should validate: %v", err)
+	}
+	data, err := Marshal(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\"my-org/*\": {\n          \"permissions\": \"read-all\"\n        }",
+		"\"my-org/my-repo\": {\n          \"permissions\": {\n            \"contents\": \"write\"\n          }\n        }",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %s\n%s", want, data)
+		}
+	}
+
+	yml, err := ImportJSONC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"      my-org/*:\n        permissions: read-all\n",
+		"      my-org/my-repo:\n        permissions:\n          contents: write\n",
+	} {
+		if !strings.Contains(string(yml), want) {
+			t.Errorf("import lost %q\n%s", want, yml)
+		}
+	}
+}
+
+func TestParseUnquotedFeatureVersion(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	body := "name: t\nimage: ubuntu\nfeatures:\n" +
+		"  ghcr.io/devcontainers/features/go:1: 1.20\n" +
+		"  ghcr.io/devcontainers/features/node:1: 22\n" +
+		"profiles:\n  ci:\n    features:\n      ghcr.io/devcontainers/features/python:1: 3.10\n"
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadYAMLFile(file, "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]string{
+		"ghcr.io/devcontainers/features/go:1":     "1.20",
+		"ghcr.io/devcontainers/features/node:1":   "22",
+		"ghcr.io/devcontainers/features/python:1": "3.10",
+	} {
+		if got := dc.Features[id].Version; got != want {
+			t.Errorf("%s version = %q, want %q", id, got, want)
+		}
+	}
+
+	for _, tc := range []struct {
+		in   any
+		want string
+	}{{1.22, "1.22"}, {int64(2), "2"}, {uint64(3), "3"}, {4, "4"}} {
+		got, err := featureOptionsDecodeHook(nil, featureOptionsType, tc.in)
+		if err != nil || got.(model.FeatureOptions).Version != tc.want {
+			t.Errorf("%v: got %v, %v; want version %s", tc.in, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package devcontainer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RulePassthrough identifies a key copied into devcontainer.json without
+// being typed or validated. It is only reported on request (convert
+// --warn-passthrough).
+const RulePassthrough = "passthrough"
+
+var (
+	devContainerType   = reflect.TypeOf(model.DevContainer{})
+	customizationsType = reflect.TypeOf(model.Customizations{})
+)
+
+// isPassthroughKey reports whether key, unknown to struct t, is copied into
+// devcontainer.json as is rather than rejected: a vendor x-* key at the top
+// level, or the customizations of a tool the model has no types for.
+func isPassthroughKey(t reflect.Type, key string) bool {
+	switch t {
+	case devContainerType:
+		return strings.HasPrefix(key, "x-")
+	case customizationsType:
+		return true
+	}
+	return false
+}
+
+// collectExtra keeps the keys mapstructure dropped: unknown top-level keys
+// and the customizations of unknown tools. ParseStrict has already rejected
+// all but the passthrough ones.
+func collectExtra(dc *model.DevContainer, raw map[string]any) {
+	dc.Extra = extraKeys(devContainerType, raw)
+	c, ok := raw["customizations"].(map[string]any)
+	if !ok {
+		return
+	}
+	if extra := extraKeys(customizationsType, c); extra != nil {
+		if dc.Customizations == nil {
+			dc.Customizations = &model.Customizations{}
+		}
+		dc.Customizations.Extra = extra
+	}
+}
+
+// extraKeys returns the entries of m that no field of struct t claims.
+func extraKeys(t reflect.Type, m map[string]any) model.Extra {
+	fields := yamlFields(t)
+	var extra model.Extra
+	for key, v := range m {
+		if _, ok := fields[key]; ok {
+			continue
+		}
+		if extra == nil {
+			extra = model.Extra{}
+		}
+		extra[key] = v
+	}
+	return extra
+}
+
+// PassthroughDiagnostics returns a warning for every key of dc that is
+// copied into devcontainer.json verbatim, so a team can see what convert
+// does not check.
+func PassthroughDiagnostics(dc *model.DevContainer, file string, pos Positions) []diagnostic.Diagnostic {
+	var out []diagnostic.Diagnostic
+	report := func(path string) {
+		out = append(out, newDiagnostic(RulePassthrough, diagnostic.SeverityWarning, file, pos, path,
+			fmt.Sprintf("Key '%s' is not part of the model; copying it to devcontainer.json unchecked.", path)))
+	}
+	for _, key := range SortedKeys(dc.Extra) {
+		report(key)
+	}
+	if dc.Customizations != nil {
+		for _, key := range SortedKeys(dc.Customizations.Extra) {
+			report(joinField("customizations", key))
+		}
+	}
+	return out
+}
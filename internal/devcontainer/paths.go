@@ -0,0 +1,149 @@
+package devcontainer
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// joinField, joinMapKey and joinIndex build the YAML paths used in every
+// diagnostic: struct fields are dotted, map keys are quoted and list items
+// are indexed, e.g. portsAttributes."3000".protocol or mounts[2].target.
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func joinMapKey(path, key string) string {
+	return joinField(path, strconv.Quote(key))
+}
+
+func joinIndex(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// splitPath breaks a YAML path into its segments, each keeping the "." or
+// "[" that introduced it, so that concatenating a prefix of the result yields
+// an ancestor path. Quoted map keys may contain dots and brackets.
+func splitPath(path string) []string {
+	var segs []string
+	for i := 0; i < len(path); {
+		start := i
+		if path[i] == '.' {
+			i++
+		}
+		switch {
+		case i < len(path) && path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				end = len(path) - i - 1
+			}
+			i += end + 1
+		case i < len(path) && path[i] == '"':
+			q, err := strconv.QuotedPrefix(path[i:])
+			if err != nil {
+				i = len(path)
+				break
+			}
+			i += len(q)
+		default:
+			for i < len(path) && path[i] != '.' && path[i] != '[' {
+				i++
+			}
+		}
+		segs = append(segs, path[start:i])
+	}
+	return segs
+}
+
+// parentPath returns the path one level above path, or "" at the top level.
+func parentPath(path string) string {
+	segs := splitPath(path)
+	if len(segs) <= 1 {
+		return ""
+	}
+	return strings.Join(segs[:len(segs)-1], "")
+}
+
+// FieldPath converts a go-playground/validator namespace such as
+// "DevContainer.Mounts[2].Mount.Target" into the YAML path of the field
+// ("mounts[2].target"). Wrapper fields of union types are elided and map keys
+// are quoted. Struct-level errors reported on the root map to "".
+func FieldPath(namespace string) string {
+	t := reflect.TypeOf(model.DevContainer{})
+	path := ""
+
+	segs := splitNamespace(namespace)
+	for _, seg := range segs[min(1, len(segs)):] {
+		name, brackets, _ := strings.Cut(seg, "[")
+		if brackets != "" {
+			brackets = "[" + brackets
+		}
+
+		t = derefType(t)
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			return path
+		}
+		if _, union := unionShapes[t]; !union {
+			path = joinField(path, yamlName(f))
+		}
+		t = f.Type
+
+		for brackets != "" {
+			end := strings.IndexByte(brackets, ']')
+			if end < 0 {
+				break
+			}
+			key := brackets[1:end]
+			brackets = brackets[end+1:]
+
+			t = derefType(t)
+			switch t.Kind() {
+			case reflect.Map:
+				path = joinMapKey(path, key)
+			case reflect.Slice, reflect.Array:
+				path += "[" + key + "]"
+			default:
+				return path
+			}
+			t = t.Elem()
+		}
+	}
+	return path
+}
+
+// splitNamespace splits a validator namespace on dots that are not inside a
+// [map key] suffix.
+func splitNamespace(ns string) []string {
+	var (
+		segs  []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(ns); i++ {
+		switch ns[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segs = append(segs, ns[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segs, ns[start:])
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
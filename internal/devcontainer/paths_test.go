@@ -0,0 +1,36 @@
+package devcontainer
+
+import "testing"
+
+func TestFieldPath(t *testing.T) {
+	cases := map[string]string{
+		"DevContainer.Name":                                   "name",
+		"DevContainer.Build.Dockerfile":                       "build.dockerfile",
+		"DevContainer.Mounts[2].Mount.Target":                 "mounts[2].target",
+		"DevContainer.PortsAttributes[3000].Protocol":         `portsAttributes."3000".protocol`,
+		"DevContainer.Secrets[a.b].Description":               `secrets."a.b".description`,
+		"DevContainer.HostRequirements.GPU.Requirement.Cores": "hostRequirements.gpu.cores",
+		"DevContainer.DevContainer":                           "",
+	}
+	for ns, want := range cases {
+		if got := FieldPath(ns); got != want {
+			t.Errorf("FieldPath(%q) = %q, want %q", ns, got, want)
+		}
+	}
+}
+
+func TestParentPath(t *testing.T) {
+	cases := map[string]string{
+		"build.dockerfile":                "build",
+		"mounts[2].target":                "mounts[2]",
+		"mounts[2]":                       "mounts",
+		`portsAttributes."3000".protocol`: `portsAttributes."3000"`,
+		`features."ghcr.io/x/go:1"`:       "features",
+		"name":                            "",
+	}
+	for path, want := range cases {
+		if got := parentPath(path); got != want {
+			t.Errorf("parentPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package devcontainer
+
+import (
+	"fmt"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RuleUnmatchedPortAttributes identifies a portsAttributes key that no
+// forwarded port falls under.
+const RuleUnmatchedPortAttributes = "unmatched-port-attributes"
+
+// PortAttributesDiagnostics warns about portsAttributes keys naming a port or
+// range that none of forwardPorts or appPort refers to. Regular expression
+// keys match processes rather than port numbers and are not checked. Nothing
+// is reported when no ports are listed, since the attributes then describe
+// automatically forwarded ports.
+func PortAttributesDiagnostics(dc *model.DevContainer, file string, pos Positions) []diagnostic.Diagnostic {
+	var ports []int
+	for _, p := range dc.ForwardPorts {
+		if _, port, err := p.Port(); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	for _, p := range dc.AppPort {
+		if port, err := p.PublishedPort(); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+
+	var out []diagnostic.Diagnostic
+	for _, key := range SortedKeys(dc.PortsAttributes) {
+		lo, hi, err := PortAttributesKey(key)
+		if err != nil || hi == 0 {
+			continue
+		}
+		matched := false
+		for _, port := range ports {
+			if port >= lo && port <= hi {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			path := joinMapKey("portsAttributes", key)
+			out = append(out, newDiagnostic(RuleUnmatchedPortAttributes, diagnostic.SeverityWarning, file, pos, path,
+				fmt.Sprintf("Key '%s' matches none of the ports in forwardPorts or appPort.", path)))
+		}
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package devcontainer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestValidatePorts(t *testing.T) {
+	dc := model.DevContainer{
+		Name:  "t",
+		Image: "ubuntu",
+		ForwardPorts: []model.PortSpec{
+			model.PortNumber(3000), model.PortString("db:5432"),
+			model.PortString("db:5432x"), model.PortNumber(70000), model.PortString("a b:1"),
+		},
+		AppPort: []model.PortSpec{
+			model.PortString("8080:3000"), model.PortString("127.0.0.1::3001/udp"),
+			model.PortString("1:2:3:4"), model.PortString("3000/sctp"),
+		},
+		PortsAttributes: map[string]*model.PortAttributes{
+			"3000": {}, "3000-3010": {}, "node.*": {},
+			"0": {}, "3010-3000": {}, "[a": {},
+		},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, d.RuleID+" "+d.Path)
+	}
+	want := []string{
+		"forward_port forwardPorts[2]", "forward_port forwardPorts[3]", "forward_port forwardPorts[4]",
+		"app_port appPort[2]", "app_port appPort[3]",
+		`port_attributes_key portsAttributes."0"`, `port_attributes_key portsAttributes."3010-3000"`, `port_attributes_key portsAttributes."[a"`,
+	}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPortAttributesDiagnostics(t *testing.T) {
+	dc := &model.DevContainer{
+		ForwardPorts: []model.PortSpec{model.PortNumber(3000), model.PortString("db:5432")},
+		AppPort:      []model.PortSpec{model.PortString("127.0.0.1:8080:3005")},
+		PortsAttributes: map[string]*model.PortAttributes{
+			"3000": {}, "5432": {}, "3001-3010": {}, "node.*": {},
+			"80": {}, "9000-9010": {},
+		},
+	}
+	var got []string
+	for _, d := range PortAttributesDiagnostics(dc, "config.yaml", nil) {
+		got = append(got, d.Path)
+	}
+	if want := []string{`portsAttributes."80"`, `portsAttributes."9000-9010"`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	dc.ForwardPorts, dc.AppPort = nil, nil
+	if ds := PortAttributesDiagnostics(dc, "config.yaml", nil); len(ds) != 0 {
+		t.Errorf("without listed ports: %v", ds)
+	}
+}
+
+func sameElements(a, b []string) bool {
+	count := map[string]int{}
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		count[s]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
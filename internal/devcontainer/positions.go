@@ -0,0 +1,204 @@
+package devcontainer
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// Position is a 1-based line and column in config.yaml, or in the extends
+// base file named by File when the key was inherited.
+type Position struct {
+	// File is the base file the key was read from, empty for the config
+	// file itself.
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Positions maps YAML paths (as built by FieldPath and UnknownKeys) to the
+// location of the key or list item in the source file.
+type Positions map[string]Position
+
+// Lookup returns the position of path, or of its nearest ancestor present in
+// the file when path itself is absent (e.g. a required field that was never
+// written is reported at its parent block).
+func (p Positions) Lookup(path string) (Position, bool) {
+	for {
+		if pos, ok := p[path]; ok {
+			return pos, true
+		}
+		if path == "" {
+			return Position{}, false
+		}
+		path = parentPath(path)
+	}
+}
+
+// Ancestry returns the positions of path and of each of its ancestors present
+// in the file, innermost first.
+func (p Positions) Ancestry(path string) []Position {
+	var out []Position
+	for {
+		if pos, ok := p[path]; ok {
+			out = append(out, pos)
+		}
+		if path == "" {
+			return out
+		}
+		path = parentPath(path)
+	}
+}
+
+// LoadPositions records where every key and list item of the config file at
+// path lives, after its extends chain and the given profiles are merged in
+// the same way as LoadYAMLFile does. Keys inherited from a base file carry
+// that file in Position.File.
+func LoadPositions(path string, profiles ...string) (Positions, error) {
+	_, o, err := loadConfigTree(path, LoadOptions{Profiles: profiles, PathsAsWritten: true})
+	if err != nil {
+		return nil, fmt.Errorf("error loading file: %w", err)
+	}
+	pos := Positions{}
+	recordPositions(reflect.TypeOf(model.DevContainer{}), o, "", pos)
+	return pos, nil
+}
+
+// origin records where a merged value was written: the position of its key
+// or list item, and the origins of its own keys or items.
+type origin struct {
+	at    Position
+	kind  yaml.Kind
+	keys  map[string]*origin
+	items []*origin
+}
+
+// newOrigin builds the origin tree of n, read from file ("" for the config
+// file itself). Aliases are followed and merge keys (<<) contribute the keys
+// they provide, like the decoder does.
+func newOrigin(n *yaml.Node, at Position, file string) *origin {
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) == 0 {
+			return &origin{at: at, kind: yaml.MappingNode}
+		}
+		return newOrigin(n.Content[0], at, file)
+	}
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	o := &origin{at: at, kind: n.Kind}
+	switch n.Kind {
+	case yaml.MappingNode:
+		o.keys = map[string]*origin{}
+		var merged []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Tag == "!!merge" {
+				merged = append(merged, v)
+				continue
+			}
+			o.keys[k.Value] = newOrigin(v, Position{File: file, Line: k.Line, Column: k.Column}, file)
+		}
+		for _, v := range merged {
+			sources := []*yaml.Node{v}
+			if v.Kind == yaml.SequenceNode {
+				sources = v.Content
+			}
+			for _, src := range sources {
+				for k, c := range newOrigin(src, at, file).keys {
+					if _, ok := o.keys[k]; !ok {
+						o.keys[k] = c
+					}
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		o.items = make([]*origin, len(n.Content))
+		for i, item := range n.Content {
+			o.items[i] = newOrigin(item, Position{File: file, Line: item.Line, Column: item.Column}, file)
+		}
+	}
+	return o
+}
+
+func (o *origin) key(k string) *origin {
+	if o == nil {
+		return nil
+	}
+	return o.keys[k]
+}
+
+func (o *origin) item(i int) *origin {
+	if o == nil || i >= len(o.items) {
+		return nil
+	}
+	return o.items[i]
+}
+
+// recordPositions walks o alongside the Go type t so that struct fields and
+// map keys get the same path syntax the validator mapping produces. A nil t
+// means a free-form value: every mapping key is treated as a map key.
+func recordPositions(t reflect.Type, o *origin, path string, pos Positions) {
+	if o == nil {
+		return
+	}
+	if t != nil {
+		t = derefType(t)
+		if shape, ok := unionShapes[t]; ok {
+			t = nil
+			if o.kind == yaml.MappingNode {
+				t = shape
+			}
+		}
+	}
+
+	switch o.kind {
+	case yaml.MappingNode:
+		var fields map[string]reflect.StructField
+		if t != nil && t.Kind() == reflect.Struct {
+			fields = yamlFields(t)
+		}
+		for k, v := range o.keys {
+			if v == nil {
+				continue
+			}
+			var (
+				p     string
+				child reflect.Type
+			)
+			if fields != nil {
+				p = joinField(path, k)
+				if f, ok := fields[k]; ok {
+					child = f.Type
+				}
+			} else {
+				p = joinMapKey(path, k)
+				if t != nil && t.Kind() == reflect.Map {
+					child = t.Elem()
+				}
+			}
+			pos[p] = v.at
+			recordPositions(child, v, p, pos)
+		}
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && t.Kind() == reflect.Slice {
+			elem = t.Elem()
+		}
+		for i, item := range o.items {
+			if item == nil {
+				continue
+			}
+			p := joinIndex(path, i)
+			pos[p] = item.at
+			recordPositions(elem, item, p, pos)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package devcontainer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RulePathEscapes identifies a relative path that resolves outside the
+// repository containing the config file.
+const RulePathEscapes = "path-escapes-repo"
+
+// RebasePaths rewrites the relative paths in dc that the spec resolves against
+// the devcontainer.json folder (build.context, build.dockerfile, dockerFile
+// and dockerComposeFile) so that a config written for a devcontainer.json in
+// from keeps pointing at the same files when written to to instead. Absolute
+// paths and paths containing ${...} variables are left alone.
+func RebasePaths(dc *model.DevContainer, from, to string) {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return
+	}
+	eachPath(dc, func(_ string, p *string) {
+		*p = rebasePath(*p, from, to)
+	})
+}
+
+// PathDiagnostics warns about the paths RebasePaths rewrites that, resolved
+// against dir, lie outside the git repository containing file: they only
+// exist on the machine the config was written on, and not in a fresh clone
+// or a codespace. Nothing is reported outside a repository.
+func PathDiagnostics(dc *model.DevContainer, dir, file string, pos Positions) []diagnostic.Diagnostic {
+	root := RepositoryRoot(filepath.Dir(file))
+	if root == "" {
+		return nil
+	}
+	var out []diagnostic.Diagnostic
+	eachPath(dc, func(path string, p *string) {
+		if !relativePath(*p) {
+			return
+		}
+		abs, err := filepath.Abs(filepath.Join(dir, *p))
+		if err != nil {
+			return
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return
+		}
+		out = append(out, newDiagnostic(RulePathEscapes, diagnostic.SeverityWarning, file, pos, path,
+			fmt.Sprintf("Field '%s': '%s' resolves to %s, outside the repository at %s.", path, *p, filepath.ToSlash(abs), filepath.ToSlash(root))))
+	})
+	return out
+}
+
+// eachPath calls fn with the YAML path and a pointer to every non-empty path
+// field the spec resolves against the devcontainer.json folder.
+func eachPath(dc *model.DevContainer, fn func(path string, p *string)) {
+	if b := dc.Build; b != nil {
+		if b.Dockerfile != "" {
+			fn("build.dockerfile", &b.Dockerfile)
+		}
+		if b.Context != "" {
+			fn("build.context", &b.Context)
+		}
+	}
+	if dc.DockerFile != "" {
+		fn("dockerFile", &dc.DockerFile)
+	}
+	for i := range dc.DockerComposeFile.Values() {
+		path := "dockerComposeFile"
+		if !dc.DockerComposeFile.Scalar {
+			path = joinIndex(path, i)
+		}
+		fn(path, &dc.DockerComposeFile.Items[i])
+	}
+}
+
+// rebaseMap is RebasePaths for a config map read from YAML, as used by
+// loadMerged on base files. m is not modified.
+func rebaseMap(m map[string]any, from, to string) map[string]any {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return m
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	rebaseValue := func(v any, fn func(any) any) any {
+		if r, ok := replaced(v); ok {
+			return map[string]any{replaceKey: fn(r)}
+		}
+		return fn(v)
+	}
+	rebaseString := func(v any) any {
+		if s, ok := v.(string); ok {
+			return rebasePath(s, from, to)
+		}
+		return v
+	}
+	if v, ok := out["build"]; ok {
+		out["build"] = rebaseValue(v, func(v any) any {
+			b, ok := v.(map[string]any)
+			if !ok {
+				return v
+			}
+			nb := make(map[string]any, len(b))
+			for k, item := range b {
+				nb[k] = item
+				if k == "dockerfile" || k == "context" {
+					nb[k] = rebaseValue(item, rebaseString)
+				}
+			}
+			return nb
+		})
+	}
+	if v, ok := out["dockerFile"]; ok {
+		out["dockerFile"] = rebaseValue(v, rebaseString)
+	}
+	if v, ok := out["dockerComposeFile"]; ok {
+		out["dockerComposeFile"] = rebaseValue(v, func(v any) any {
+			list, ok := v.([]any)
+			if !ok {
+				return rebaseString(v)
+			}
+			nl := make([]any, len(list))
+			for i, item := range list {
+				nl[i] = rebaseString(item)
+			}
+			return nl
+		})
+	}
+	return out
+}
+
+func rebasePath(p, from, to string) string {
+	if !relativePath(p) {
+		return p
+	}
+	rel, err := filepath.Rel(to, filepath.Join(from, p))
+	if err != nil {
+		return p
+	}
+	return filepath.ToSlash(rel)
+}
+
+// relativePath reports whether p is a path to rebase: neither empty, absolute
+// nor built from ${...} variables.
+func relativePath(p string) bool {
+	return p != "" && !filepath.IsAbs(p) && !strings.Contains(p, "${")
+}
+
+// RepositoryRoot walks up from dir to the nearest directory containing .git
+// and returns it as an absolute path, or "" outside a repository.
+func RepositoryRoot(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for d := abs; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return ""
+		}
+		d = parent
+	}
+}
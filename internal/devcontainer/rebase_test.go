@@ -0,0 +1,52 @@
+package devcontainer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestRebasePaths(t *testing.T) {
+	dc := model.DevContainer{
+		Build:             &model.BuildConfig{Dockerfile: "Dockerfile", Context: ".."},
+		DockerFile:        "../docker/Dockerfile",
+		DockerComposeFile: model.StringSlicePtr([]string{"docker-compose.yml", "/abs/compose.yml", "${localWorkspaceFolder}/compose.yml"}),
+	}
+	RebasePaths(&dc, ".devcontainer", ".devcontainer/python")
+
+	if dc.Build.Dockerfile != "../Dockerfile" || dc.Build.Context != "../.." {
+		t.Errorf("build = %+v", dc.Build)
+	}
+	if dc.DockerFile != "../../docker/Dockerfile" {
+		t.Errorf("dockerFile = %q", dc.DockerFile)
+	}
+	want := []string{"../docker-compose.yml", "/abs/compose.yml", "${localWorkspaceFolder}/compose.yml"}
+	if !reflect.DeepEqual(dc.DockerComposeFile.Items, want) {
+		t.Errorf("dockerComposeFile = %v, want %v", dc.DockerComposeFile, want)
+	}
+}
+
+func TestPathDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	writeFiles(t, dir, map[string]string{"repo/.git/HEAD": "ref: refs/heads/main\n"})
+	dc := &model.DevContainer{
+		Build:             &model.BuildConfig{Dockerfile: "Dockerfile", Context: ".."},
+		DockerComposeFile: model.StringSlicePtr([]string{"../shared/compose.yml", "${localEnv:HOME}/compose.yml", "/abs/compose.yml"}),
+	}
+	file := filepath.Join(repo, "config.yaml")
+
+	var paths []string
+	for _, d := range PathDiagnostics(dc, repo, file, nil) {
+		paths = append(paths, d.Path)
+	}
+	if want := []string{"build.context", "dockerComposeFile[0]"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+
+	if ds := PathDiagnostics(dc, dir, filepath.Join(dir, "config.yaml"), nil); len(ds) != 0 {
+		t.Errorf("outside a repository got %v", ds)
+	}
+}
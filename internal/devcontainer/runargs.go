@@ -0,0 +1,178 @@
+package devcontainer
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RuleRunArgs identifies a runArgs flag LiftRunArgs recognised but had to
+// leave in runArgs.
+const RuleRunArgs = "run-args"
+
+// runFlag is a docker run flag in runArgs that a typed field of
+// model.DevContainer can express.
+type runFlag struct {
+	index int    // of the flag in runArgs
+	span  int    // runArgs entries it takes: 2 for "--cap-add", "SYS_PTRACE"
+	name  string // privileged, init, cap-add, security-opt, device or env
+	value string // "true" or "false" for privileged and init
+}
+
+// typedRunFlags returns the flags of args that have a typed field, in the
+// forms docker run accepts: "--flag value", "--flag=value", "-e value" and
+// "-evalue". Boolean flags may carry "=true" or "=false".
+func typedRunFlags(args []string) []runFlag {
+	var out []runFlag
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		switch name {
+		case "--privileged", "--init":
+			if !hasValue {
+				value = "true"
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				continue
+			}
+			out = append(out, runFlag{index: i, span: 1, name: name[2:], value: strconv.FormatBool(b)})
+		case "--cap-add", "--security-opt", "--device", "--env", "-e":
+			f := runFlag{index: i, span: 1, name: strings.TrimLeft(name, "-"), value: value}
+			if name == "-e" {
+				f.name = "env"
+			}
+			if !hasValue {
+				if i+1 >= len(args) {
+					continue
+				}
+				f.value, f.span = args[i+1], 2
+				i++
+			}
+			out = append(out, f)
+		default:
+			if strings.HasPrefix(args[i], "-e") && !strings.HasPrefix(args[i], "--") && len(args[i]) > 2 {
+				out = append(out, runFlag{index: i, span: 1, name: "env", value: args[i][2:]})
+			}
+		}
+	}
+	return out
+}
+
+// typedRunArg compares f with the typed field that expresses it. It returns
+// the YAML path of that field when f repeats or contradicts it, and whether
+// f contradicts it; path is empty when the field does not mention f.
+func typedRunArg(dc *model.DevContainer, f runFlag) (path string, conflict bool) {
+	switch f.name {
+	case "privileged", "init":
+		set := dc.Privileged
+		if f.name == "init" {
+			set = dc.Init
+		}
+		if set {
+			return f.name, f.value == "false"
+		}
+	case "cap-add":
+		if i := slices.IndexFunc(dc.CapAdd, func(c string) bool { return sameCapability(c, f.value) }); i >= 0 {
+			return joinIndex("capAdd", i), false
+		}
+	case "security-opt":
+		if i := slices.IndexFunc(dc.SecurityOpt, func(o string) bool { return sameSecurityOpt(o, f.value) }); i >= 0 {
+			return joinIndex("securityOpt", i), false
+		}
+	case "device":
+		if i := slices.Index(dc.Devices, f.value); i >= 0 {
+			return joinIndex("devices", i), false
+		}
+	case "env":
+		k, v, ok := strings.Cut(f.value, "=")
+		if cur, set := dc.ContainerEnv[k]; ok && set {
+			return joinMapKey("containerEnv", k), cur != v
+		}
+	}
+	return "", false
+}
+
+// CapabilityName normalizes a Linux capability the way Docker reads it:
+// upper case and without the CAP_ prefix, so "cap_sys_admin" is "SYS_ADMIN".
+func CapabilityName(c string) string {
+	return strings.TrimPrefix(strings.ToUpper(c), "CAP_")
+}
+
+// sameCapability compares capabilities as Docker does.
+func sameCapability(a, b string) bool {
+	return CapabilityName(a) == CapabilityName(b)
+}
+
+// sameSecurityOpt compares security options as Docker does, which also
+// accepts the older "seccomp:unconfined" form of "seccomp=unconfined".
+func sameSecurityOpt(a, b string) bool {
+	norm := func(o string) string {
+		if i := strings.IndexAny(o, ":="); i >= 0 && o[i] == ':' {
+			return o[:i] + "=" + o[i+1:]
+		}
+		return o
+	}
+	return norm(a) == norm(b)
+}
+
+// LiftRunArgs moves the runArgs flags that have a typed field into it:
+// --privileged, --init, --cap-add, --security-opt, --device and -e/--env. Flags
+// already expressed by the typed field are dropped. "-e NAME" without a value
+// passes the host's variable, which containerEnv cannot express; it stays in
+// runArgs and is reported as a warning. runArgs are left alone with Docker
+// Compose, which ignores them while applying the typed fields. The config must
+// have passed validation, which rejects runArgs contradicting a typed field.
+func LiftRunArgs(dc *model.DevContainer, file string, pos Positions) []diagnostic.Diagnostic {
+	if len(dc.DockerComposeFile.Values()) > 0 {
+		return nil
+	}
+	var (
+		out    []diagnostic.Diagnostic
+		lifted = map[int]bool{}
+	)
+	for _, f := range typedRunFlags(dc.RunArgs) {
+		// A repeated flag contradicting an earlier one wins, as in docker run.
+		if path, conflict := typedRunArg(dc, f); path == "" || conflict {
+			switch f.name {
+			case "privileged":
+				dc.Privileged = f.value == "true"
+			case "init":
+				dc.Init = f.value == "true"
+			case "cap-add":
+				dc.CapAdd = append(dc.CapAdd, f.value)
+			case "security-opt":
+				dc.SecurityOpt = append(dc.SecurityOpt, f.value)
+			case "device":
+				dc.Devices = append(dc.Devices, f.value)
+			case "env":
+				k, v, ok := strings.Cut(f.value, "=")
+				if !ok {
+					path := joinIndex("runArgs", f.index)
+					out = append(out, newDiagnostic(RuleRunArgs, diagnostic.SeverityWarning, file, pos, path,
+						fmt.Sprintf("Field '%s' passes the host's %s, which containerEnv cannot express; keeping it in runArgs. Set '%s' to \"${localEnv:%s}\" to move it.", path, k, joinMapKey("containerEnv", k), k)))
+					continue
+				}
+				if dc.ContainerEnv == nil {
+					dc.ContainerEnv = map[string]string{}
+				}
+				dc.ContainerEnv[k] = v
+			}
+		}
+		for j := range f.span {
+			lifted[f.index+j] = true
+		}
+	}
+
+	var kept []string
+	for i, a := range dc.RunArgs {
+		if !lifted[i] {
+			kept = append(kept, a)
+		}
+	}
+	dc.RunArgs = kept
+	return out
+}
@@ -0,0 +1,49 @@
+package devcontainer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestLiftRunArgs(t *testing.T) {
+	dc := model.DevContainer{
+		Image:        "ubuntu",
+		CapAdd:       []string{"SYS_PTRACE"},
+		SecurityOpt:  []string{"seccomp=unconfined"},
+		ContainerEnv: map[string]string{"FOO": "bar"},
+		RunArgs: []string{
+			"--privileged", "--cap-add=cap_sys_ptrace", "--cap-add", "NET_ADMIN",
+			"--security-opt", "seccomp:unconfined", "--device=/dev/fuse",
+			"-e", "FOO=bar", "--env=BAZ=1", "-eQUX=2", "-e", "HOME_DIR",
+			"--init=false", "--network=host",
+		},
+	}
+	ds := LiftRunArgs(&dc, "", nil)
+
+	if !dc.Privileged || dc.Init {
+		t.Errorf("privileged = %v, init = %v", dc.Privileged, dc.Init)
+	}
+	if want := []string{"SYS_PTRACE", "NET_ADMIN"}; !reflect.DeepEqual(dc.CapAdd, want) {
+		t.Errorf("capAdd = %v, want %v", dc.CapAdd, want)
+	}
+	if !reflect.DeepEqual(dc.SecurityOpt, []string{"seccomp=unconfined"}) || !reflect.DeepEqual(dc.Devices, []string{"/dev/fuse"}) {
+		t.Errorf("securityOpt = %v, devices = %v", dc.SecurityOpt, dc.Devices)
+	}
+	if want := map[string]string{"FOO": "bar", "BAZ": "1", "QUX": "2"}; !reflect.DeepEqual(dc.ContainerEnv, want) {
+		t.Errorf("containerEnv = %v, want %v", dc.ContainerEnv, want)
+	}
+	if want := []string{"-e", "HOME_DIR", "--network=host"}; !reflect.DeepEqual(dc.RunArgs, want) {
+		t.Errorf("runArgs = %v, want %v", dc.RunArgs, want)
+	}
+	if len(ds) != 1 || ds[0].Path != "runArgs[11]" {
+		t.Errorf("diagnostics = %v, want one for runArgs[11]", ds)
+	}
+
+	compose := model.DevContainer{DockerComposeFile: model.SingleStringPtr("compose.yml"), RunArgs: []string{"--privileged"}}
+	LiftRunArgs(&compose, "", nil)
+	if compose.Privileged || len(compose.RunArgs) != 1 {
+		t.Errorf("runArgs lifted with Docker Compose: %+v", compose)
+	}
+}
@@ -0,0 +1,113 @@
+package devcontainer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RuleEmptyVariable identifies a variable reference that Substitute resolved
+// to an empty string.
+const RuleEmptyVariable = "empty-variable"
+
+// Host is the machine and workspace variables are resolved for.
+type Host struct {
+	// LocalWorkspaceFolder is the absolute path of the folder opened in the
+	// editor, usually the repository root.
+	LocalWorkspaceFolder string
+	// ConfigFile is the absolute path of the devcontainer.json; together with
+	// LocalWorkspaceFolder it determines ${devcontainerId}.
+	ConfigFile string
+	// LookupEnv reads the local environment; nil means os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+}
+
+// Substitute replaces the variable references in dc with the values they take
+// on host, as the devcontainer CLI does when it starts the container. A
+// ${containerEnv:VAR} reference is resolved from dc's own containerEnv and
+// otherwise left in place, since its value is only known inside the
+// container; unknown variables are left in place too. A warning is returned
+// for each reference that resolves to an empty string.
+func Substitute(dc *model.DevContainer, host Host, file string, pos Positions) []diagnostic.Diagnostic {
+	lookup := host.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	values := map[string]string{
+		"localWorkspaceFolder":         host.LocalWorkspaceFolder,
+		"localWorkspaceFolderBasename": filepath.Base(host.LocalWorkspaceFolder),
+		"devcontainerId":               DevcontainerID(host.LocalWorkspaceFolder, host.ConfigFile),
+	}
+
+	var out []diagnostic.Diagnostic
+	resolve := func(p, s string) string {
+		vars, _ := ParseVariables(s)
+		var b strings.Builder
+		last := 0
+		for _, v := range vars {
+			val, ok := values[v.Name]
+			switch v.Name {
+			case "localEnv", "env":
+				if val, _ = lookup(v.Arg); val == "" {
+					val = v.Default
+				}
+				ok = true
+			case "containerEnv":
+				// containerEnv is walked before remoteEnv, so its values are
+				// already resolved here.
+				val, ok = dc.ContainerEnv[v.Arg]
+			}
+			if !ok {
+				continue
+			}
+			if val == "" {
+				out = append(out, newDiagnostic(RuleEmptyVariable, diagnostic.SeverityWarning, file, pos, p,
+					fmt.Sprintf("Field '%s': '%s' resolves to an empty string.", p, v.Raw)))
+			}
+			b.WriteString(s[last:v.Start])
+			b.WriteString(val)
+			last = v.End
+		}
+		b.WriteString(s[last:])
+		return b.String()
+	}
+
+	// The container workspace folder may itself use local variables, so it is
+	// resolved before anything refers to it.
+	folder := resolve("workspaceFolder", dc.WorkspaceFolder)
+	switch {
+	case folder != "":
+	case len(dc.DockerComposeFile.Values()) > 0:
+		folder = "/"
+	default:
+		folder = path.Join("/workspaces", values["localWorkspaceFolderBasename"])
+	}
+	values["containerWorkspaceFolder"] = folder
+	values["containerWorkspaceFolderBasename"] = path.Base(folder)
+
+	out = out[:0] // reported again by the walk below
+	walkStrings(reflect.ValueOf(dc).Elem(), "", resolve)
+	return out
+}
+
+// DevcontainerID computes ${devcontainerId} the way the devcontainer CLI
+// does: a SHA-256 of the container's identifying labels, written in base 32
+// and padded to 52 characters.
+func DevcontainerID(localFolder, configFile string) string {
+	labels, _ := json.Marshal(map[string]string{
+		"devcontainer.config_file":  configFile,
+		"devcontainer.local_folder": localFolder,
+	})
+	sum := sha256.Sum256(labels)
+	id := new(big.Int).SetBytes(sum[:]).Text(32)
+	return strings.Repeat("0", 52-len(id)) + id
+}
@@ -0,0 +1,49 @@
+package devcontainer
+
+import (
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestSubstitute(t *testing.T) {
+	dc := &model.DevContainer{
+		Name:              "dev-${devcontainerId}",
+		DockerComposeFile: model.SingleStringPtr("compose.yml"),
+		Mounts:            []model.MountOrString{{Str: "source=${localEnv:HOME}/.ssh,target=/root/.ssh,type=bind"}},
+		PostCreateCommand: &model.CommandValue{Items: []string{"ls ${containerWorkspaceFolder} ${localEnv:MISSING:none}"}},
+	}
+	host := Host{
+		LocalWorkspaceFolder: "/home/me/proj",
+		ConfigFile:           "/home/me/proj/.devcontainer/devcontainer.json",
+		LookupEnv: func(name string) (string, bool) {
+			if name == "HOME" {
+				return "/home/me", true
+			}
+			return "", false
+		},
+	}
+	if ds := Substitute(dc, host, "config.yaml", nil); len(ds) != 0 {
+		t.Errorf("unexpected diagnostics: %v", ds)
+	}
+
+	if want := "dev-" + DevcontainerID(host.LocalWorkspaceFolder, host.ConfigFile); dc.Name != want {
+		t.Errorf("name = %q, want %q", dc.Name, want)
+	}
+	if got := dc.Mounts[0].Str; got != "source=/home/me/.ssh,target=/root/.ssh,type=bind" {
+		t.Errorf("mount = %q", got)
+	}
+	if got := dc.PostCreateCommand.Items[0]; got != "ls / none" {
+		t.Errorf("postCreateCommand = %q", got)
+	}
+}
+
+func TestDevcontainerID(t *testing.T) {
+	id := DevcontainerID("/a", "/a/.devcontainer/devcontainer.json")
+	if len(id) != 52 {
+		t.Errorf("len(%q) = %d, want 52", id, len(id))
+	}
+	if id == DevcontainerID("/b", "/b/.devcontainer/devcontainer.json") {
+		t.Error("different workspaces share an id")
+	}
+}
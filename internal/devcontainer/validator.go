@@ -2,7 +2,9 @@ package devcontainer
 
 import (
 	"errors"
-	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/lucasassuncao/devcontainerwizard/internal/model"
@@ -13,11 +15,89 @@ import (
 func Validate(dc model.DevContainer) error {
 	v := validator.New()
 	v.RegisterStructValidation(DevContainerStructLevelValidation, model.DevContainer{})
+	v.RegisterStructValidation(MountOrStringStructLevelValidation, model.MountOrString{})
+	v.RegisterStructValidation(FeatureOptionsStructLevelValidation, model.FeatureOptions{})
+	v.RegisterCustomTypeFunc(func(f reflect.Value) any { return f.Interface().(model.PortSpec).String() }, model.PortSpec{})
+	_ = v.RegisterValidation("forward_port", func(fl validator.FieldLevel) bool {
+		_, _, err := model.PortString(fl.Field().String()).Port()
+		return err == nil
+	})
+	_ = v.RegisterValidation("app_port", func(fl validator.FieldLevel) bool {
+		_, err := model.PortString(fl.Field().String()).PublishedPort()
+		return err == nil
+	})
+	_ = v.RegisterValidation("port_attributes_key", func(fl validator.FieldLevel) bool {
+		_, _, err := PortAttributesKey(fl.Field().String())
+		return err == nil
+	})
+	_ = v.RegisterValidation("codespaces_repository", func(fl validator.FieldLevel) bool {
+		return codespacesRepositoryRe.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("env_name", func(fl validator.FieldLevel) bool {
+		return envNameRe.MatchString(fl.Field().String())
+	})
 	return v.Struct(dc)
 }
 
+// codespacesRepositoryRe matches a customizations.codespaces.repositories
+// key: a repository ("owner/repo") or every repository of an owner ("owner/*").
+var codespacesRepositoryRe = regexp.MustCompile(`^[A-Za-z0-9-]+/([A-Za-z0-9._-]+|\*)$`)
+
+// envNameRe matches a POSIX environment variable name.
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// FeatureOptionsStructLevelValidation rejects feature options whose value is
+// not a string, boolean or number; features take no nested options.
+func FeatureOptionsStructLevelValidation(sl validator.StructLevel) {
+	f, ok := sl.Current().Interface().(model.FeatureOptions)
+	if !ok {
+		return
+	}
+	for _, k := range SortedKeys(f.Options) {
+		switch f.Options[k].(type) {
+		case map[string]any, []any:
+			sl.ReportError(f.Options[k], "Options["+k+"]", "Options", "feature_option", "")
+		}
+	}
+}
+
+// PortAttributesKey parses a portsAttributes key: a port ("3000"), an
+// inclusive range ("3000-3010"), or a regular expression matched against the
+// command line of the process listening on the port. lo and hi are the
+// bounds of the range (equal for a single port) and zero for an expression.
+func PortAttributesKey(key string) (lo, hi int, err error) {
+	if first, last, ok := strings.Cut(key, "-"); ok && isDigits(first) && isDigits(last) {
+		if lo, err = model.ParsePortNumber(first); err != nil {
+			return 0, 0, err
+		}
+		if hi, err = model.ParsePortNumber(last); err != nil {
+			return 0, 0, err
+		}
+		if lo > hi {
+			return 0, 0, errors.New("range start is greater than its end")
+		}
+		return lo, hi, nil
+	}
+	if isDigits(key) {
+		lo, err = model.ParsePortNumber(key)
+		return lo, lo, err
+	}
+	_, err = regexp.Compile(key)
+	return 0, 0, err
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
 // DevContainerStructLevelValidation enforces that exactly one of Image, Build
-// or DockerComposeFile is set on a DevContainer.
+// or DockerComposeFile is set on a DevContainer, and the spec rules that
+// relate one field to another: workspaceMount needs a workspaceFolder,
+// waitFor must name a lifecycle command that is set, overrideFeatureInstallOrder
+// may only name declared features, shutdownAction must suit the kind of
+// container, and runArgs must not contradict the typed fields. runArgs that
+// repeat a typed field, and the docker run options ignored with Docker
+// Compose, are reported as warnings.
 func DevContainerStructLevelValidation(sl validator.StructLevel) {
 	dc, ok := sl.Current().Interface().(model.DevContainer)
 	if !ok {
@@ -34,7 +114,8 @@ func DevContainerStructLevelValidation(sl validator.StructLevel) {
 	if dc.DockerFile != "" {
 		count++
 	}
-	if len(dc.DockerComposeFile) > 0 {
+	compose := len(dc.DockerComposeFile.Values()) > 0
+	if compose {
 		count++
 	}
 
@@ -44,48 +125,149 @@ func DevContainerStructLevelValidation(sl validator.StructLevel) {
 	if count > 1 {
 		sl.ReportError(dc, "DevContainer", "", "mutually_exclusive", "")
 	}
+
+	if dc.WorkspaceMount != "" && dc.WorkspaceFolder == "" {
+		sl.ReportError(dc.WorkspaceMount, "WorkspaceMount", "WorkspaceMount", "workspace_mount", "")
+	}
+	if cmd, ok := lifecycleCommands(dc)[dc.WaitFor]; ok && cmd == nil {
+		sl.ReportError(dc.WaitFor, "WaitFor", "WaitFor", "wait_for", dc.WaitFor)
+	}
+	if len(dc.OverrideFeatureInstallOrder) > 0 {
+		declared := map[string]bool{}
+		for id := range dc.Features {
+			declared[BareFeatureID(id)] = true
+		}
+		for i, id := range dc.OverrideFeatureInstallOrder {
+			if !declared[BareFeatureID(id)] {
+				sl.ReportError(id, "OverrideFeatureInstallOrder["+strconv.Itoa(i)+"]", "OverrideFeatureInstallOrder", "feature_install_order", id)
+			}
+		}
+	}
+
+	for _, f := range typedRunFlags(dc.RunArgs) {
+		path, conflict := typedRunArg(&dc, f)
+		if path == "" || compose {
+			continue
+		}
+		tag := "run_arg_duplicate"
+		if conflict {
+			tag = "run_arg_conflict"
+		}
+		sl.ReportError(dc.RunArgs[f.index], "RunArgs["+strconv.Itoa(f.index)+"]", "RunArgs", tag, path)
+	}
+
+	switch {
+	case dc.ShutdownAction == "stopCompose" && !compose:
+		sl.ReportError(dc.ShutdownAction, "ShutdownAction", "ShutdownAction", "stop_compose", "")
+	case dc.ShutdownAction == "stopContainer" && compose:
+		sl.ReportError(dc.ShutdownAction, "ShutdownAction", "ShutdownAction", "stop_container", "")
+	}
+	if compose {
+		if len(dc.RunArgs) > 0 {
+			sl.ReportError(dc.RunArgs, "RunArgs", "RunArgs", "compose_ignored", "")
+		}
+		if len(dc.AppPort) > 0 {
+			sl.ReportError(dc.AppPort, "AppPort", "AppPort", "compose_ignored", "")
+		}
+		if dc.WorkspaceMount != "" {
+			sl.ReportError(dc.WorkspaceMount, "WorkspaceMount", "WorkspaceMount", "compose_ignored", "")
+		}
+	}
+}
+
+// lifecycleCommands maps each command waitFor may name to its value in dc.
+func lifecycleCommands(dc model.DevContainer) map[string]*model.CommandValue {
+	return map[string]*model.CommandValue{
+		"initializeCommand":    dc.InitializeCommand,
+		"onCreateCommand":      dc.OnCreateCommand,
+		"updateContentCommand": dc.UpdateContentCommand,
+		"postCreateCommand":    dc.PostCreateCommand,
+		"postStartCommand":     dc.PostStartCommand,
+	}
+}
+
+// bareFeatureID removes the version tag or digest from a feature ID, the
+// form overrideFeatureInstallOrder uses.
+func BareFeatureID(id string) string {
+	if i := strings.Index(id, "@"); i >= 0 {
+		id = id[:i]
+	}
+	if i := strings.LastIndex(id, ":"); i > strings.LastIndex(id, "/") {
+		id = id[:i]
+	}
+	return id
+}
+
+// MountOrStringStructLevelValidation parses Docker --mount strings and applies
+// the rules of model.Mount to the result, so "target=/x" fails like an object
+// mount without a type would.
+func MountOrStringStructLevelValidation(sl validator.StructLevel) {
+	m, ok := sl.Current().Interface().(model.MountOrString)
+	if !ok || m.Str == "" {
+		return
+	}
+	spec, err := model.ParseMountString(m.Str)
+	if err != nil {
+		sl.ReportError(m.Str, "Str", "Str", "mount", err.Error())
+		return
+	}
+	var errs validator.ValidationErrors
+	if errors.As(sl.Validator().Struct(spec.Mount), &errs) {
+		sl.ReportValidationErrors("", "", errs)
+	}
 }
 
 // validationMessages maps a validator tag to a fmt template. Placeholders:
 //
-//	%[1]s → field name (empty for struct-level errors)
+//	%[1]s → YAML path of the field (empty for struct-level errors)
 //	%[2]s → tag param (e.g. allowed values for oneof, threshold for gt/lt)
 //
 // Tags absent from the map fall through to a generic "failed validation" line.
+// Tags in validationWarnings are reported as warnings, not errors.
 var validationMessages = map[string]string{
-	"required":           "Field '%[1]s' is required.",
-	"one_required":       "At least one of the fields 'Image', 'Build', 'DockerFile' or 'DockerComposeFile' must be set.",
-	"mutually_exclusive": "Only one of the fields 'Image', 'Build', 'DockerFile' or 'DockerComposeFile' can be set at a time.",
-	"file":               "Field '%[1]s' must point to a valid file path.",
-	"dir":                "Field '%[1]s' must point to a valid directory path.",
-	"oneof":              "Field '%[1]s' must be one of the following values: %[2]s.",
-	"gt":                 "Field '%[1]s' must be greater than %[2]s.",
-	"lt":                 "Field '%[1]s' must be less than %[2]s.",
-	"dive":               "Field '%[1]s' contains invalid nested elements.",
-	"keys":               "Field '%[1]s' has invalid map keys.",
-	"endkeys":            "Field '%[1]s' has invalid map values.",
-	"omitempty":          "Field '%[1]s' is optional but invalid when provided.",
+	"required":              "Field '%[1]s' is required.",
+	"one_required":          "At least one of the fields 'image', 'build', 'dockerFile' or 'dockerComposeFile' must be set.",
+	"mutually_exclusive":    "Only one of the fields 'image', 'build', 'dockerFile' or 'dockerComposeFile' can be set at a time.",
+	"file":                  "Field '%[1]s' must point to a valid file path.",
+	"dir":                   "Field '%[1]s' must point to a valid directory path.",
+	"url":                   "Field '%[1]s' must be a valid URL.",
+	"oneof":                 "Field '%[1]s' must be one of the following values: %[2]s.",
+	"mount":                 "Field '%[1]s' is not a valid mount: %[2]s.",
+	"feature_option":        "Field '%[1]s' must be a string, boolean or number; features take no nested options.",
+	"forward_port":          "Field '%[1]s' must be a port number (1-65535), \"host:port\" or \"service:port\".",
+	"app_port":              "Field '%[1]s' must be a port number (1-65535) or a Docker publish spec such as \"8080:3000\".",
+	"port_attributes_key":   "Key '%[1]s' must be a port (3000), a port range (3000-3010) or a regular expression.",
+	"env_name":              "Key '%[1]s' must be a valid environment variable name: letters, digits and underscores, not starting with a digit.",
+	"codespaces_repository": "Key '%[1]s' must name a repository as owner/repo, or all of an owner's repositories as owner/*.",
+	"workspace_mount":       "Field '%[1]s' needs 'workspaceFolder', the path it mounts the workspace to.",
+	"wait_for":              "Field '%[1]s' names '%[2]s', which is not set.",
+	"feature_install_order": "Field '%[1]s' names '%[2]s', which is not declared in 'features'.",
+	"stop_compose":          "Field '%[1]s' can only be 'stopCompose' with 'dockerComposeFile'; use 'stopContainer' or 'none'.",
+	"stop_container":        "Field '%[1]s' cannot be 'stopContainer' with 'dockerComposeFile'; use 'stopCompose' or 'none'.",
+	"compose_ignored":       "Field '%[1]s' is a docker run option and is ignored with 'dockerComposeFile'; set it on the service in the compose file instead.",
+	"run_arg_duplicate":     "Field '%[1]s' repeats '%[2]s'; remove it from runArgs.",
+	"run_arg_conflict":      "Field '%[1]s' contradicts '%[2]s' and overrides it, since runArgs are applied last.",
+	"gt":                    "Field '%[1]s' must be greater than %[2]s.",
+	"lt":                    "Field '%[1]s' must be less than %[2]s.",
+	"dive":                  "Field '%[1]s' contains invalid nested elements.",
+	"keys":                  "Field '%[1]s' has invalid map keys.",
+	"endkeys":               "Field '%[1]s' has invalid map values.",
+	"omitempty":             "Field '%[1]s' is optional but invalid when provided.",
 }
 
-func HumanizeValidationError(err error) string {
-	if err == nil {
-		return ""
-	}
+// validationWarnings lists the tags for settings that are ignored rather than
+// invalid.
+var validationWarnings = map[string]bool{
+	"compose_ignored":   true,
+	"run_arg_duplicate": true,
+}
 
+// HumanizeValidationError renders each validator failure on its own line,
+// naming fields by their YAML path (build.dockerfile, mounts[2].target).
+func HumanizeValidationError(err error) string {
 	var sb strings.Builder
-
-	var errs validator.ValidationErrors
-	if errors.As(err, &errs) {
-		for _, e := range errs {
-			tmpl, ok := validationMessages[e.Tag()]
-			if !ok {
-				tmpl = "Field '%[1]s' failed validation '" + e.Tag() + "'."
-			}
-			fmt.Fprintf(&sb, tmpl+"\n", e.Field(), e.Param())
-		}
-	} else {
-		sb.WriteString(err.Error())
+	for _, d := range ValidationDiagnostics(err, "", nil) {
+		sb.WriteString(d.Message + "\n")
 	}
-
 	return strings.TrimSpace(sb.String())
 }
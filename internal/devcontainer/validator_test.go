@@ -0,0 +1,203 @@
+package devcontainer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestValidateCodespaces(t *testing.T) {
+	dc := model.DevContainer{
+		Name:  "t",
+		Image: "ubuntu",
+		Customizations: &model.Customizations{
+			Codespaces: &model.CodespacesCustomization{
+				Repositories: map[string]model.CodespacesRepository{
+					"my-org/my-repo": {Permissions: model.PermissionScopesPtr(map[string]string{"contents": "write", "pull_requests": "read"})},
+					"my-org/*":       {Permissions: model.PermissionScopesPtr(map[string]string{"issues": "admin", "secrets": "read"})},
+					"my-repo":        {Permissions: model.PermissionScopesPtr(map[string]string{"contents": "read"})},
+					"my-org/other":   {},
+					"my-org/all":     {Permissions: model.PermissionsAllPtr("write-all")},
+					"my-org/bad":     {Permissions: model.PermissionsAllPtr("admin-all")},
+				},
+				OpenFiles: []string{"README.md", ""},
+			},
+		},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, d.RuleID+" "+d.Path)
+	}
+	want := []string{
+		`oneof customizations.codespaces.repositories."my-org/*".permissions."issues"`,
+		`oneof customizations.codespaces.repositories."my-org/*".permissions."secrets"`,
+		`codespaces_repository customizations.codespaces.repositories."my-repo"`,
+		`required customizations.codespaces.repositories."my-org/other".permissions`,
+		`oneof customizations.codespaces.repositories."my-org/bad".permissions`,
+		"required customizations.codespaces.openFiles[1]",
+	}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValidateEnvNames(t *testing.T) {
+	dc := model.DevContainer{
+		Name:         "t",
+		Image:        "ubuntu",
+		ContainerEnv: map[string]string{"GOPATH": "/go", "_X1": "", "1X": "", "MY-VAR": ""},
+		RemoteEnv:    map[string]*string{"HTTP_PROXY": nil, "A B": nil},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, d.RuleID+" "+d.Path)
+	}
+	want := []string{
+		`env_name containerEnv."1X"`, `env_name containerEnv."MY-VAR"`, `env_name remoteEnv."A B"`,
+	}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValidateCrossFieldRules(t *testing.T) {
+	onCreate := model.CommandString("make")
+	dc := model.DevContainer{
+		Name:            "t",
+		Image:           "ubuntu",
+		WorkspaceMount:  "source=${localWorkspaceFolder},target=/src,type=bind",
+		WaitFor:         "postCreateCommand",
+		OnCreateCommand: &onCreate,
+		ShutdownAction:  "stopCompose",
+		Features:        map[string]model.FeatureOptions{"ghcr.io/devcontainers/features/git:1": model.FeatureVersion("latest")},
+		OverrideFeatureInstallOrder: []string{
+			"ghcr.io/devcontainers/features/git",
+			"ghcr.io/devcontainers/features/node",
+		},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, d.RuleID+" "+d.Path)
+	}
+	want := []string{
+		"workspace_mount workspaceMount",
+		"wait_for waitFor",
+		"feature_install_order overrideFeatureInstallOrder[1]",
+		"stop_compose shutdownAction",
+	}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	compose := model.DevContainer{
+		Name:              "t",
+		DockerComposeFile: model.SingleStringPtr("compose.yml"),
+		Service:           "app",
+		WorkspaceFolder:   "/src",
+		RunArgs:           []string{"--init"},
+		AppPort:           []model.PortSpec{model.PortNumber(3000)},
+		ShutdownAction:    "stopContainer",
+	}
+	got = nil
+	for _, d := range ValidationDiagnostics(Validate(compose), "", nil) {
+		got = append(got, string(d.Severity)+" "+d.RuleID+" "+d.Path)
+	}
+	want = []string{
+		"warning compose_ignored runArgs",
+		"warning compose_ignored appPort",
+		"error stop_container shutdownAction",
+	}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValidateRunArgs(t *testing.T) {
+	dc := model.DevContainer{
+		Name:         "t",
+		Image:        "ubuntu",
+		Privileged:   true,
+		CapAdd:       []string{"CAP_SYS_PTRACE"},
+		ContainerEnv: map[string]string{"FOO": "bar", "BAZ": "1"},
+		SecurityOpt:  []string{"seccomp=unconfined"},
+		RunArgs:      []string{"--privileged=false", "--cap-add", "sys_ptrace", "-e", "FOO=bar", "--env=BAZ=2", "--init", "--security-opt=seccomp:unconfined"},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, string(d.Severity)+" "+d.RuleID+" "+d.Path+" "+d.Message)
+	}
+	want := []string{
+		"error run_arg_conflict runArgs[0] Field 'runArgs[0]' contradicts 'privileged' and overrides it, since runArgs are applied last.",
+		"warning run_arg_duplicate runArgs[1] Field 'runArgs[1]' repeats 'capAdd[0]'; remove it from runArgs.",
+		"warning run_arg_duplicate runArgs[3] Field 'runArgs[3]' repeats 'containerEnv.\"FOO\"'; remove it from runArgs.",
+		"error run_arg_conflict runArgs[5] Field 'runArgs[5]' contradicts 'containerEnv.\"BAZ\"' and overrides it, since runArgs are applied last.",
+		"warning run_arg_duplicate runArgs[7] Field 'runArgs[7]' repeats 'securityOpt[0]'; remove it from runArgs.",
+	}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValidateSecrets(t *testing.T) {
+	dc := model.DevContainer{
+		Name:  "t",
+		Image: "ubuntu",
+		Secrets: map[string]model.Secret{
+			"API_KEY":   {},
+			"GH_TOKEN":  {Description: "token", DocumentationURL: "https://docs.github.com"},
+			"NPM_TOKEN": {DocumentationURL: "not a url"},
+		},
+	}
+	var got []string
+	for _, d := range ValidationDiagnostics(Validate(dc), "", nil) {
+		got = append(got, d.RuleID+" "+d.Path)
+	}
+	want := []string{`url secrets."NPM_TOKEN".documentationUrl`}
+	if !sameElements(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValidateWorkspaceMountPosition(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"config.yaml": `name: t
+image: ubuntu
+workspaceMount: source=${localWorkspaceFolder},target=/src,type=bind
+`})
+	path := filepath.Join(dir, "config.yaml")
+	k, err := LoadYAMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := ParseStrict(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos, err := LoadPositions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := ValidationDiagnostics(Validate(dc), "config.yaml", pos)
+	if len(ds) != 1 {
+		t.Fatalf("expected one diagnostic, got %+v", ds)
+	}
+	if d := ds[0]; d.RuleID != "workspace_mount" || d.Line != 3 || d.Column != 1 {
+		t.Errorf("got %s at %d:%d, want workspace_mount at 3:1", d.RuleID, d.Line, d.Column)
+	}
+}
+
+func TestBareFeatureID(t *testing.T) {
+	for id, want := range map[string]string{
+		"ghcr.io/devcontainers/features/go:1":            "ghcr.io/devcontainers/features/go",
+		"ghcr.io/devcontainers/features/go@sha256:abc":   "ghcr.io/devcontainers/features/go",
+		"ghcr.io/devcontainers/features/go":              "ghcr.io/devcontainers/features/go",
+		"localhost:5000/features/go:1":                   "localhost:5000/features/go",
+		"localhost:5000/features/go":                     "localhost:5000/features/go",
+		"ghcr.io/devcontainers/features/go:1@sha256:abc": "ghcr.io/devcontainers/features/go",
+	} {
+		if got := BareFeatureID(id); got != want {
+			t.Errorf("BareFeatureID(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
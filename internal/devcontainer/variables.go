@@ -0,0 +1,243 @@
+package devcontainer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// RuleVariableSyntax, RuleUnknownVariable and RuleVariableContext identify
+// problems with ${...} variable references found by VariableDiagnostics.
+const (
+	RuleVariableSyntax  = "variable-syntax"
+	RuleUnknownVariable = "unknown-variable"
+	RuleVariableContext = "variable-context"
+)
+
+// Variable is a single ${...} reference in a string.
+type Variable struct {
+	Raw        string // the whole reference, e.g. "${localEnv:HOME:/root}"
+	Name       string // "localEnv"
+	Arg        string // "HOME"; only for variables that take one
+	Default    string // "/root"
+	HasDefault bool
+	Start, End int // byte offsets of Raw in the string
+}
+
+// variableSpec describes a variable defined by the spec.
+type variableSpec struct {
+	takesArg bool
+	// replacedBy names the variable to use instead of a deprecated alias.
+	replacedBy string
+	// allowedIn lists the top-level keys the variable may appear under; nil
+	// means anywhere.
+	allowedIn map[string]bool
+}
+
+// variableSpecs are the variables of the Dev Containers spec.
+var variableSpecs = map[string]variableSpec{
+	"localEnv":                         {takesArg: true},
+	"env":                              {takesArg: true, replacedBy: "localEnv"},
+	"containerEnv":                     {takesArg: true, allowedIn: map[string]bool{"remoteEnv": true}},
+	"localWorkspaceFolder":             {},
+	"localWorkspaceFolderBasename":     {},
+	"containerWorkspaceFolder":         {},
+	"containerWorkspaceFolderBasename": {},
+	"devcontainerId":                   {allowedIn: devcontainerIDKeys()},
+}
+
+// devcontainerIDKeys are the properties the spec allows ${devcontainerId} in:
+// name, lifecycle hooks, mounts, containerEnv, remoteEnv, containerUser,
+// remoteUser and customizations.
+func devcontainerIDKeys() map[string]bool {
+	keys := map[string]bool{
+		"name": true, "mounts": true, "containerEnv": true, "remoteEnv": true,
+		"containerUser": true, "remoteUser": true, "customizations": true,
+	}
+	for k := range commandFields {
+		keys[k] = true
+	}
+	return keys
+}
+
+// ParseVariables returns the ${...} references in s. Malformed references
+// (an unterminated "${", an empty or nested one, or a variable taking an
+// argument used without one) are reported as an error alongside the
+// references that did parse. Unknown names are not an error here.
+func ParseVariables(s string) ([]Variable, error) {
+	var (
+		vars []Variable
+		errs []string
+	)
+	for i := 0; i < len(s); {
+		start := strings.Index(s[i:], "${")
+		if start < 0 {
+			break
+		}
+		start += i
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			errs = append(errs, fmt.Sprintf("unterminated variable reference '%s'", s[start:]))
+			break
+		}
+		end += start + 1
+		raw := s[start:end]
+		body := raw[2 : len(raw)-1]
+		i = end
+
+		if body == "" || strings.Contains(body, "${") {
+			errs = append(errs, fmt.Sprintf("malformed variable reference '%s'", raw))
+			continue
+		}
+		v := Variable{Raw: raw, Start: start, End: end}
+		parts := strings.SplitN(body, ":", 3)
+		v.Name = parts[0]
+		if len(parts) > 1 {
+			v.Arg = parts[1]
+		}
+		if len(parts) > 2 {
+			v.Default, v.HasDefault = parts[2], true
+		}
+		if spec, ok := variableSpecs[v.Name]; ok {
+			switch {
+			case spec.takesArg && v.Arg == "":
+				errs = append(errs, fmt.Sprintf("'%s' needs a variable name, e.g. ${%s:HOME}", raw, v.Name))
+				continue
+			case !spec.takesArg && len(parts) > 1:
+				errs = append(errs, fmt.Sprintf("'%s' does not take an argument; use ${%s}", raw, v.Name))
+				continue
+			}
+		}
+		vars = append(vars, v)
+	}
+	if len(errs) > 0 {
+		return vars, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return vars, nil
+}
+
+// VariableDiagnostics checks every ${...} reference in the string values of
+// dc: syntax, known variable names (with a suggestion for wrong casing or
+// typos) and the context rules of the spec.
+func VariableDiagnostics(dc *model.DevContainer, file string, pos Positions) []diagnostic.Diagnostic {
+	var out []diagnostic.Diagnostic
+	add := func(rule, path, msg string) {
+		out = append(out, newDiagnostic(rule, diagnostic.SeverityError, file, pos, path, msg))
+	}
+	warn := func(rule, path, msg string) {
+		out = append(out, newDiagnostic(rule, diagnostic.SeverityWarning, file, pos, path, msg))
+	}
+	walkStrings(reflect.ValueOf(dc).Elem(), "", func(path, s string) string {
+		vars, err := ParseVariables(s)
+		if err != nil {
+			add(RuleVariableSyntax, path, fmt.Sprintf("Field '%s': %v.", path, err))
+		}
+		top := ""
+		if segs := splitPath(path); len(segs) > 0 {
+			top = segs[0]
+		}
+		for _, v := range vars {
+			spec, ok := variableSpecs[v.Name]
+			if !ok {
+				msg := fmt.Sprintf("Field '%s' uses unknown variable '%s'", path, v.Raw)
+				if sug := suggestVariable(v.Name); sug != "" {
+					msg += fmt.Sprintf(" (did you mean '%s'?)", sug)
+				}
+				add(RuleUnknownVariable, path, msg+".")
+				continue
+			}
+			if spec.replacedBy != "" {
+				warn(RuleUnknownVariable, path, fmt.Sprintf("Field '%s' uses deprecated '%s'; use ${%s:%s} instead.",
+					path, v.Raw, spec.replacedBy, v.Arg))
+			}
+			if spec.allowedIn != nil && !spec.allowedIn[top] {
+				add(RuleVariableContext, path, fmt.Sprintf("Field '%s' cannot use '%s'; ${%s} is only substituted in %s.",
+					path, v.Raw, v.Name, strings.Join(SortedKeys(spec.allowedIn), ", ")))
+			}
+		}
+		return s
+	})
+	return out
+}
+
+// suggestVariable returns the spec variable closest to name.
+func suggestVariable(name string) string {
+	best, bestDist := "", 3
+	for known, spec := range variableSpecs {
+		if spec.replacedBy != "" {
+			continue
+		}
+		if strings.EqualFold(known, name) {
+			return known
+		}
+		if d := editDistance(name, known); d < bestDist || d == bestDist && known < best {
+			best, bestDist = known, d
+		}
+	}
+	return best
+}
+
+// walkStrings calls fn with the YAML path of every string reachable from v
+// and, where v is settable, replaces the string with what fn returns. Wrapper
+// fields of union types are elided from the path, as in FieldPath.
+func walkStrings(v reflect.Value, path string, fn func(path, s string) string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkStrings(v.Elem(), path, fn)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		e := reflect.New(v.Elem().Type()).Elem()
+		e.Set(v.Elem())
+		walkStrings(e, path, fn)
+		if v.CanSet() {
+			v.Set(e)
+		}
+	case reflect.String:
+		if s := v.String(); s != "" {
+			if r := fn(path, s); r != s && v.CanSet() {
+				v.SetString(r)
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		_, union := unionShapes[t]
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			switch {
+			case union:
+				walkStrings(v.Field(i), path, fn)
+			case yamlName(f) != "":
+				walkStrings(v.Field(i), joinField(path, yamlName(f)), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkStrings(v.Index(i), joinIndex(path, i), fn)
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			// Map elements are not addressable: rewrite a copy and store it back.
+			kv := reflect.ValueOf(k).Convert(v.Type().Key())
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(kv))
+			walkStrings(e, joinMapKey(path, k), fn)
+			v.SetMapIndex(kv, e)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package devcontainer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestParseVariables(t *testing.T) {
+	vars, err := ParseVariables("${localEnv:HOME:/root:x}/src/${containerWorkspaceFolderBasename}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vars) != 2 {
+		t.Fatalf("got %d variables, want 2", len(vars))
+	}
+	if v := vars[0]; v.Name != "localEnv" || v.Arg != "HOME" || !v.HasDefault || v.Default != "/root:x" {
+		t.Errorf("vars[0] = %+v", v)
+	}
+	if v := vars[1]; v.Name != "containerWorkspaceFolderBasename" || v.Arg != "" || v.HasDefault {
+		t.Errorf("vars[1] = %+v", v)
+	}
+
+	for _, s := range []string{"${localEnv:X", "${}", "${localEnv}", "${localWorkspaceFolder:x}"} {
+		if _, err := ParseVariables(s); err == nil {
+			t.Errorf("ParseVariables(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestVariableDiagnostics(t *testing.T) {
+	path := "${containerEnv:PATH}:/bin"
+	dc := &model.DevContainer{
+		Name:           "dev-${devcontainerId}",
+		WorkspaceMount: "source=${localWorkspaceFolder},target=/ws/${devcontainerId},type=bind",
+		ContainerEnv: map[string]string{
+			"A": "${localenv:HOME}",
+			"B": "${containerEnv:PATH}",
+			"C": "${env:USER}",
+		},
+		RemoteEnv:         map[string]*string{"PATH": &path, "UNSET": nil},
+		PostCreateCommand: &model.CommandValue{Items: []string{"echo ${workspaceFolder}"}},
+	}
+	got := map[string]diagnostic.Diagnostic{}
+	for _, d := range VariableDiagnostics(dc, "config.yaml", nil) {
+		got[d.Path] = d
+	}
+
+	want := map[string]struct {
+		rule string
+		sev  diagnostic.Severity
+		msg  string
+	}{
+		"workspaceMount":       {RuleVariableContext, diagnostic.SeverityError, "${devcontainerId} is only substituted in"},
+		`containerEnv."A"`:     {RuleUnknownVariable, diagnostic.SeverityError, "did you mean 'localEnv'?"},
+		`containerEnv."B"`:     {RuleVariableContext, diagnostic.SeverityError, "only substituted in remoteEnv"},
+		`containerEnv."C"`:     {RuleUnknownVariable, diagnostic.SeverityWarning, "use ${localEnv:USER}"},
+		"postCreateCommand[0]": {RuleUnknownVariable, diagnostic.SeverityError, "'${workspaceFolder}'"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got diagnostics for %d paths, want %d: %v", len(got), len(want), got)
+	}
+	for path, w := range want {
+		d, ok := got[path]
+		if !ok {
+			t.Errorf("no diagnostic for %s", path)
+			continue
+		}
+		if d.RuleID != w.rule || d.Severity != w.sev || !strings.Contains(d.Message, w.msg) {
+			t.Errorf("%s: got %s %s %q, want %s %s containing %q", path, d.RuleID, d.Severity, d.Message, w.rule, w.sev, w.msg)
+		}
+	}
+}
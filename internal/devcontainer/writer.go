@@ -9,17 +9,30 @@ import (
 	"github.com/lucasassuncao/devcontainerwizard/internal/model"
 )
 
-// WriteFile serialises dc as JSON to outputPath. Parent directories are created
-// as needed. When force is false, returns an error if outputPath already exists.
-// Returns the cleaned absolute-or-relative path actually written.
-func WriteFile(dc model.DevContainer, outputPath string, force bool) (string, error) {
+// defaultSchema is the $schema stamped on every generated devcontainer.json.
+const defaultSchema = "https://containers.dev/implementors/json_schema"
+
+// Marshal renders dc as the indented JSON that WriteFile writes, filling in
+// the default $schema when none is set.
+func Marshal(dc model.DevContainer) ([]byte, error) {
 	if dc.Schema == "" {
-		dc.Schema = "https://containers.dev/implementors/json_schema"
+		dc.Schema = defaultSchema
 	}
 
 	jsonBytes, err := json.MarshalIndent(dc, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("error marshalling JSON: %w", err)
+		return nil, fmt.Errorf("error marshalling JSON: %w", err)
+	}
+	return jsonBytes, nil
+}
+
+// WriteFile serialises dc as JSON to outputPath. Parent directories are created
+// as needed. When force is false, returns an error if outputPath already exists.
+// Returns the cleaned absolute-or-relative path actually written.
+func WriteFile(dc model.DevContainer, outputPath string, force bool) (string, error) {
+	jsonBytes, err := Marshal(dc)
+	if err != nil {
+		return "", err
 	}
 
 	parent := filepath.Dir(outputPath)
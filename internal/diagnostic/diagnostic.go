@@ -0,0 +1,108 @@
+// Package diagnostic defines the structured findings reported by convert and
+// the other checking commands, and renders them as text, JSON or SARIF.
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Severity ranks a diagnostic. Only errors fail a command.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+// Diagnostic is a single finding about a config file.
+type Diagnostic struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Path     string   `json:"path,omitempty"` // YAML path of the offending key, if any
+}
+
+// Location renders "file:line:col", dropping the parts that are unknown.
+func (d Diagnostic) Location() string {
+	switch {
+	case d.Line > 0 && d.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	case d.Line > 0:
+		return fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+	return d.File
+}
+
+// String renders d in the compiler-style form used by the text format.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", d.Location(), d.Severity, d.Message, d.RuleID)
+}
+
+// HasErrors reports whether any diagnostic has SeverityError.
+func HasErrors(ds []Diagnostic) bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort orders diagnostics by file, line and column so output follows the
+// source rather than the order checks happened to run in.
+func Sort(ds []Diagnostic) {
+	sort.SliceStable(ds, func(i, j int) bool {
+		a, b := ds[i], ds[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// Formats lists the values accepted by Write.
+var Formats = []string{"text", "json", "sarif"}
+
+// Write renders ds to w in the given format. toolVersion is recorded in SARIF
+// output.
+func Write(w io.Writer, format string, ds []Diagnostic, toolVersion string) error {
+	switch format {
+	case "", "text":
+		return WriteText(w, ds)
+	case "json":
+		return WriteJSON(w, ds)
+	case "sarif":
+		return WriteSARIF(w, ds, toolVersion)
+	}
+	return fmt.Errorf("unknown format %q (want one of %v)", format, Formats)
+}
+
+// WriteText writes one line per diagnostic.
+func WriteText(w io.Writer, ds []Diagnostic) error {
+	for _, d := range ds {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes ds as an indented JSON array (never null).
+func WriteJSON(w io.Writer, ds []Diagnostic) error {
+	if ds == nil {
+		ds = []Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ds)
+}
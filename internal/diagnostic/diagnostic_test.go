@@ -0,0 +1,91 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var sample = []Diagnostic{
+	{RuleID: "required", Severity: SeverityError, Message: "Field 'name' is required.", File: "config.yaml", Line: 3, Column: 5, Path: "name"},
+	{RuleID: "compose_ignored", Severity: SeverityWarning, Message: "Field 'runArgs' is ignored.", File: "config.yaml", Line: 7},
+	{RuleID: "drift", Severity: SeverityNote, Message: "Output is up to date.", File: "devcontainer.json"},
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "text", sample, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := "config.yaml:3:5: error: Field 'name' is required. [required]\n" +
+		"config.yaml:7: warning: Field 'runArgs' is ignored. [compose_ignored]\n" +
+		"devcontainer.json: note: Output is up to date. [drift]\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := WriteText(&buf, nil); err != nil || buf.Len() != 0 {
+		t.Errorf("no diagnostics wrote %q, %v", buf.String(), err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "json", sample, ""); err != nil {
+		t.Fatal(err)
+	}
+	var got []Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, sample) {
+		t.Errorf("round trip = %+v, want %+v", got, sample)
+	}
+
+	var raw []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw[2]["line"]; ok {
+		t.Errorf("unknown line was written: %v", raw[2])
+	}
+	if raw[0]["severity"] != "error" || raw[1]["severity"] != "warning" || raw[2]["severity"] != "note" {
+		t.Errorf("severities = %v, %v, %v", raw[0]["severity"], raw[1]["severity"], raw[2]["severity"])
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("no diagnostics = %q, want an empty array", got)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xml", sample, ""); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestSortAndHasErrors(t *testing.T) {
+	ds := []Diagnostic{
+		{RuleID: "c", File: "b.yaml", Line: 1},
+		{RuleID: "b", File: "a.yaml", Line: 4, Column: 2},
+		{RuleID: "a", File: "a.yaml", Line: 4, Column: 1},
+	}
+	Sort(ds)
+	var got []string
+	for _, d := range ds {
+		got = append(got, d.RuleID)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+
+	if HasErrors(sample[1:]) || !HasErrors(sample) {
+		t.Error("HasErrors should only count SeverityError")
+	}
+}
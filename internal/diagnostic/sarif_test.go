@@ -0,0 +1,78 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "sarif", sample, "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("tool version = %q", run.Tool.Driver.Version)
+	}
+	var rules []string
+	for _, r := range run.Tool.Driver.Rules {
+		rules = append(rules, r.ID)
+	}
+	if want := "compose_ignored,drift,required"; strings.Join(rules, ",") != want {
+		t.Errorf("rules = %v, want %s", rules, want)
+	}
+
+	want := []struct {
+		level        string
+		line, column int
+	}{
+		{"error", 3, 5},
+		{"warning", 7, 0},
+		{"note", 1, 0}, // anchored to line 1, since code scanning needs a region
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(run.Results), len(want))
+	}
+	for i, r := range run.Results {
+		region := r.Locations[0].PhysicalLocation.Region
+		if r.Level != want[i].level || region.StartLine != want[i].line || region.StartColumn != want[i].column {
+			t.Errorf("result %d: %s at %d:%d, want %s at %d:%d", i, r.Level, region.StartLine, region.StartColumn, want[i].level, want[i].line, want[i].column)
+		}
+		if r.RuleID != sample[i].RuleID || r.Message.Text != sample[i].Message || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != sample[i].File {
+			t.Errorf("result %d = %+v, want %+v", i, r, sample[i])
+		}
+	}
+}
+
+func TestWriteSARIFEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []any `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []any `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	// Code scanning rejects null, so empty lists must still be arrays.
+	if len(raw.Runs) != 1 || raw.Runs[0].Results == nil || raw.Runs[0].Tool.Driver.Rules == nil {
+		t.Errorf("empty log = %s", buf.String())
+	}
+}
@@ -50,6 +50,7 @@ func NewSchemaGenerator(docsDir, schemasDir string, opts ...Option) (*SchemaGene
 		reflector: &jsonschema.Reflector{
 			RequiredFromJSONSchemaTags: true,
 			DoNotReference:             true,
+			Mapper:                     mapType,
 		},
 		docsDir:         docsDir,
 		schemasDir:      schemasDir,
@@ -61,6 +62,19 @@ func NewSchemaGenerator(docsDir, schemasDir string, opts ...Option) (*SchemaGene
 	return g, nil
 }
 
+// mapType returns the schema of types that reflection describes wrongly. A
+// nil value in a map of string pointers, such as remoteEnv, is written as
+// null, so the values are strings or null.
+func mapType(t reflect.Type) *jsonschema.Schema {
+	if t == reflect.TypeOf(map[string]*string{}) {
+		return &jsonschema.Schema{
+			Type:                 "object",
+			AdditionalProperties: &jsonschema.Schema{Extras: map[string]any{"type": []string{"string", "null"}}},
+		}
+	}
+	return nil
+}
+
 // GenerateSchemaAndDocs generates JSON schema and markdown docs for the given type
 func (g *SchemaGenerator) GenerateSchemaAndDocs(v any) error {
 	t := reflect.TypeOf(v)
@@ -45,6 +45,9 @@ func (g *SchemaGenerator) describeType(s *jsonschema.Schema) string {
 		if elem == "-" || elem == "object" || strings.HasPrefix(elem, "map[]") || strings.HasPrefix(elem, "array[") {
 			elem = "object"
 		}
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
 		return "map[string]" + elem
 	}
 
@@ -60,6 +63,18 @@ func (g *SchemaGenerator) describeType(s *jsonschema.Schema) string {
 		return s.Type
 	}
 
+	// Lists of types and unions name each alternative, e.g. "integer | string".
+	if types, ok := s.Extras["type"].([]string); ok {
+		return strings.Join(types, " | ")
+	}
+	if len(s.OneOf) > 0 {
+		alts := make([]string, 0, len(s.OneOf))
+		for _, alt := range s.OneOf {
+			alts = append(alts, g.describeType(alt))
+		}
+		return strings.Join(alts, " | ")
+	}
+
 	if s.Properties != nil && s.Properties.Len() > 0 {
 		return "object"
 	}
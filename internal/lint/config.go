@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+)
+
+// DefaultConfigFile is the lint config picked up from the working directory
+// when no --lint-config is given.
+const DefaultConfigFile = ".devcontainerwizard-lint.yaml"
+
+// Config overrides rule severities. A rule set to "off" is disabled; rules
+// not listed keep their default severity.
+//
+//	rules:
+//	  latest-tag: error
+//	  remote-user-root: off
+type Config struct {
+	Rules map[string]string `yaml:"rules"`
+}
+
+// severity returns the effective severity of r and whether it is enabled.
+func (c Config) severity(r Rule) (diagnostic.Severity, bool) {
+	s, ok := c.Rules[r.ID]
+	if !ok {
+		return r.Severity, true
+	}
+	if s == "off" {
+		return "", false
+	}
+	return diagnostic.Severity(s), true
+}
+
+// LoadConfig reads a lint config file. A missing file yields the zero Config
+// when optional is true, which is how the default file is loaded.
+func LoadConfig(path string, optional bool) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied config path
+	if errors.Is(err, os.ErrNotExist) && optional {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("error loading lint config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("error loading lint config %s: %w", path, err)
+	}
+	for id, s := range cfg.Rules {
+		if _, ok := FindRule(id); !ok {
+			return cfg, fmt.Errorf("lint config %s: unknown rule '%s' (known rules: %s)", path, id, strings.Join(ruleIDs(), ", "))
+		}
+		switch s {
+		case "off", string(diagnostic.SeverityError), string(diagnostic.SeverityWarning), string(diagnostic.SeverityNote):
+		default:
+			return cfg, fmt.Errorf("lint config %s: rule '%s' has invalid severity '%s' (want error, warning, note or off)", path, id, s)
+		}
+	}
+	return cfg, nil
+}
+
+func ruleIDs() []string {
+	ids := make([]string, len(Rules))
+	for i, r := range Rules {
+		ids[i] = r.ID
+	}
+	return ids
+}
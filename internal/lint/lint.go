@@ -0,0 +1,108 @@
+// Package lint checks a parsed dev container config for risky settings that
+// are valid according to the spec but worth a second look, such as privileged
+// containers or a mounted Docker socket.
+package lint
+
+import (
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// Rule is a named check over a DevContainer.
+type Rule struct {
+	ID          string
+	Severity    diagnostic.Severity // default severity, overridable by Config
+	Description string
+	Check       func(dc *model.DevContainer) []Finding
+}
+
+// Finding is a single rule hit. Path is the YAML path of the offending value
+// and is used both for its position and for inline suppression.
+type Finding struct {
+	Path    string
+	Message string
+}
+
+// Rules lists every lint rule in the order they run.
+var Rules = []Rule{
+	{
+		ID:          "privileged",
+		Severity:    diagnostic.SeverityError,
+		Description: "The container runs with privileged: true, which gives it full access to the host.",
+		Check:       checkPrivileged,
+	},
+	{
+		ID:          "dangerous-capability",
+		Severity:    diagnostic.SeverityWarning,
+		Description: "capAdd grants a capability (SYS_ADMIN, NET_ADMIN or ALL) that weakens container isolation.",
+		Check:       checkCapabilities,
+	},
+	{
+		ID:          "seccomp-unconfined",
+		Severity:    diagnostic.SeverityWarning,
+		Description: "securityOpt disables the seccomp profile with seccomp=unconfined.",
+		Check:       checkSeccomp,
+	},
+	{
+		ID:          "docker-socket",
+		Severity:    diagnostic.SeverityWarning,
+		Description: "A mount exposes /var/run/docker.sock, giving the container control of the host's Docker daemon.",
+		Check:       checkDockerSocket,
+	},
+	{
+		ID:          "latest-tag",
+		Severity:    diagnostic.SeverityWarning,
+		Description: "image uses the :latest tag (explicitly or by omitting a tag), so rebuilds are not reproducible.",
+		Check:       checkLatestTag,
+	},
+	{
+		ID:          "remote-user-root",
+		Severity:    diagnostic.SeverityWarning,
+		Description: "remoteUser is root, so editor tools and terminals run as root.",
+		Check:       checkRemoteUserRoot,
+	},
+	{
+		ID:          "host-network",
+		Severity:    diagnostic.SeverityWarning,
+		Description: "runArgs attach the container to the host network with --network=host.",
+		Check:       checkHostNetwork,
+	},
+}
+
+// FindRule returns the rule with the given ID.
+func FindRule(id string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Run applies every enabled rule to dc and returns the findings that are not
+// suppressed inline, positioned using pos.
+func Run(dc *model.DevContainer, cfg Config, file string, pos devcontainer.Positions, sup Suppressions) []diagnostic.Diagnostic {
+	var out []diagnostic.Diagnostic
+	for _, r := range Rules {
+		sev, enabled := cfg.severity(r)
+		if !enabled {
+			continue
+		}
+		for _, f := range r.Check(dc) {
+			if sup.Suppressed(r.ID, pos.Ancestry(f.Path)) {
+				continue
+			}
+			d := diagnostic.Diagnostic{
+				RuleID:   r.ID,
+				Severity: sev,
+				Message:  f.Message,
+				File:     file,
+				Path:     f.Path,
+			}
+			pos.Place(&d)
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func ruleHits(dc *model.DevContainer) map[string][]string {
+	hits := map[string][]string{}
+	for _, d := range Run(dc, Config{}, "config.yaml", nil, nil) {
+		hits[d.RuleID] = append(hits[d.RuleID], d.Path)
+	}
+	return hits
+}
+
+func TestRules(t *testing.T) {
+	dc := &model.DevContainer{
+		Image:       "ghcr.io/acme/dev:latest",
+		RemoteUser:  "root",
+		Privileged:  true,
+		CapAdd:      []string{"SYS_PTRACE", "cap_sys_admin", "NET_ADMIN"},
+		SecurityOpt: []string{"label=disable", "seccomp:unconfined"},
+		Mounts: []model.MountOrString{
+			model.MountObject(model.Mount{Type: "bind", Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"}),
+			model.MountString("source=cache,target=/cache,type=volume"),
+			model.MountString("type=bind,src=/var/run/docker.sock,dst=/var/run/docker.sock"),
+		},
+		RunArgs: []string{"--cpus=2", "--network", "host", "--net=host"},
+	}
+	want := map[string][]string{
+		"privileged":           {"privileged"},
+		"dangerous-capability": {"capAdd[1]", "capAdd[2]"},
+		"seccomp-unconfined":   {"securityOpt[1]"},
+		"docker-socket":        {"mounts[0]", "mounts[2]"},
+		"latest-tag":           {"image"},
+		"remote-user-root":     {"remoteUser"},
+		"host-network":         {"runArgs[1]", "runArgs[3]"},
+	}
+	got := ruleHits(dc)
+	for _, r := range Rules {
+		if len(got[r.ID]) != len(want[r.ID]) {
+			t.Errorf("%s: got %v, want %v", r.ID, got[r.ID], want[r.ID])
+			continue
+		}
+		for i := range want[r.ID] {
+			if got[r.ID][i] != want[r.ID][i] {
+				t.Errorf("%s: got %v, want %v", r.ID, got[r.ID], want[r.ID])
+			}
+		}
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	cases := []struct {
+		ref    string
+		tag    string
+		digest bool
+	}{
+		{"ubuntu", "", false},
+		{"ubuntu:22.04", "22.04", false},
+		{"localhost:5000/dev", "", false},
+		{"localhost:5000/dev:latest", "latest", false},
+		{"ubuntu@sha256:abc", "", true},
+	}
+	for _, c := range cases {
+		tag, digest := imageTag(c.ref)
+		if tag != c.tag || digest != c.digest {
+			t.Errorf("imageTag(%q) = %q, %v; want %q, %v", c.ref, tag, digest, c.tag, c.digest)
+		}
+	}
+}
+
+func TestConfigSeverityOverrides(t *testing.T) {
+	dc := &model.DevContainer{Image: "ubuntu", Privileged: true}
+	cfg := Config{Rules: map[string]string{"privileged": "off", "latest-tag": "error"}}
+	ds := Run(dc, cfg, "config.yaml", nil, nil)
+	if len(ds) != 1 || ds[0].RuleID != "latest-tag" || ds[0].Severity != diagnostic.SeverityError {
+		t.Errorf("unexpected diagnostics: %+v", ds)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadConfig(filepath.Join(dir, DefaultConfigFile), true); err != nil {
+		t.Errorf("missing optional config: %v", err)
+	}
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml"), false); err == nil {
+		t.Error("expected error for missing explicit config")
+	}
+
+	for body, wantErr := range map[string]bool{
+		"rules:\n  latest-tag: note\n  privileged: off\n": false,
+		"rules:\n  latset-tag: off\n":                     true,
+		"rules:\n  latest-tag: fatal\n":                   true,
+		"rule:\n  latest-tag: off\n":                      true,
+	} {
+		path := filepath.Join(dir, "lint.yaml")
+		if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path, false); (err != nil) != wantErr {
+			t.Errorf("LoadConfig(%q) error = %v, want error %v", body, err, wantErr)
+		}
+	}
+}
+
+func TestInlineSuppression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	body := `name: t
+image: ubuntu # devcontainerwizard:ignore latest-tag
+remoteUser: root # devcontainerwizard:ignore privileged
+# devcontainerwizard:ignore
+capAdd:
+  - SYS_ADMIN
+runArgs:
+  - --network=host # devcontainerwizard:ignore host-network, privileged
+`
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := devcontainer.LoadYAMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc, err := devcontainer.Parse(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos, err := devcontainer.LoadPositions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sup, err := LoadSuppressions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := Run(&dc, Config{}, "config.yaml", pos, sup)
+	if len(ds) != 1 || ds[0].RuleID != "remote-user-root" || ds[0].Line != 3 {
+		t.Errorf("expected only remote-user-root at line 3, got %+v", ds)
+	}
+}
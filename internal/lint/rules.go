@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// dockerSocket is the host path of the Docker daemon socket.
+const dockerSocket = "/var/run/docker.sock"
+
+// dangerousCapabilities are the capAdd values reported by dangerous-capability.
+var dangerousCapabilities = []string{"SYS_ADMIN", "NET_ADMIN", "ALL"}
+
+func checkPrivileged(dc *model.DevContainer) []Finding {
+	if !dc.Privileged {
+		return nil
+	}
+	return []Finding{{Path: "privileged", Message: "Container runs privileged; prefer adding only the capabilities it needs."}}
+}
+
+func checkCapabilities(dc *model.DevContainer) []Finding {
+	var out []Finding
+	for i, c := range dc.CapAdd {
+		name := devcontainer.CapabilityName(c)
+		for _, d := range dangerousCapabilities {
+			if name == d {
+				out = append(out, Finding{
+					Path:    indexPath("capAdd", i),
+					Message: fmt.Sprintf("Capability '%s' weakens container isolation.", c),
+				})
+			}
+		}
+	}
+	return out
+}
+
+func checkSeccomp(dc *model.DevContainer) []Finding {
+	var out []Finding
+	for i, o := range dc.SecurityOpt {
+		if strings.ReplaceAll(o, ":", "=") == "seccomp=unconfined" {
+			out = append(out, Finding{
+				Path:    indexPath("securityOpt", i),
+				Message: "seccomp=unconfined disables system call filtering.",
+			})
+		}
+	}
+	return out
+}
+
+func checkDockerSocket(dc *model.DevContainer) []Finding {
+	var out []Finding
+	for i, m := range dc.Mounts {
+		if spec, err := m.Spec(); err == nil && spec.Source == dockerSocket {
+			out = append(out, Finding{
+				Path:    indexPath("mounts", i),
+				Message: "Mounting " + dockerSocket + " gives the container root-equivalent control of the host.",
+			})
+		}
+	}
+	return out
+}
+
+func checkLatestTag(dc *model.DevContainer) []Finding {
+	if dc.Image == "" {
+		return nil
+	}
+	tag, pinned := imageTag(dc.Image)
+	switch {
+	case pinned:
+		return nil
+	case tag == "latest":
+		return []Finding{{Path: "image", Message: fmt.Sprintf("Image '%s' uses the latest tag; pin a version or digest.", dc.Image)}}
+	case tag == "":
+		return []Finding{{Path: "image", Message: fmt.Sprintf("Image '%s' has no tag and resolves to latest; pin a version or digest.", dc.Image)}}
+	}
+	return nil
+}
+
+// imageTag returns the tag of an image reference and whether it is pinned by
+// digest. A ":port" in the registry host is not mistaken for a tag.
+func imageTag(ref string) (tag string, digest bool) {
+	if strings.Contains(ref, "@") {
+		return "", true
+	}
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:], false
+	}
+	return "", false
+}
+
+func checkRemoteUserRoot(dc *model.DevContainer) []Finding {
+	if dc.RemoteUser != "root" {
+		return nil
+	}
+	return []Finding{{Path: "remoteUser", Message: "remoteUser is root; use a non-root user such as vscode."}}
+}
+
+func checkHostNetwork(dc *model.DevContainer) []Finding {
+	var out []Finding
+	for i, a := range dc.RunArgs {
+		var host bool
+		switch a {
+		case "--network=host", "--net=host":
+			host = true
+		case "--network", "--net":
+			host = i+1 < len(dc.RunArgs) && dc.RunArgs[i+1] == "host"
+		}
+		if host {
+			out = append(out, Finding{
+				Path:    indexPath("runArgs", i),
+				Message: "--network=host shares the host's network stack with the container.",
+			})
+		}
+	}
+	return out
+}
+
+func indexPath(field string, i int) string {
+	return field + "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+)
+
+// ignoreDirective starts an inline suppression comment:
+//
+//	privileged: true # devcontainerwizard:ignore privileged
+//
+// It may list several rule IDs separated by spaces or commas; with none it
+// suppresses every rule. The comment applies to the key or list item it is
+// written on (or directly above) and to everything nested under it.
+const ignoreDirective = "devcontainerwizard:ignore"
+
+// Suppressions maps a source line to the rules ignored there. An empty list
+// means every rule.
+type Suppressions map[int][]string
+
+// Suppressed reports whether rule is ignored at any of the given positions,
+// typically a finding's path and its ancestors. Positions in extends base
+// files are skipped: s only holds the comments of the config file itself.
+func (s Suppressions) Suppressed(rule string, at []devcontainer.Position) bool {
+	for _, p := range at {
+		if p.File != "" {
+			continue
+		}
+		rules, ok := s[p.Line]
+		if ok && (len(rules) == 0 || slices.Contains(rules, rule)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSuppressions collects the ignore comments of the YAML file at path.
+func LoadSuppressions(path string) (Suppressions, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied config path
+	if err != nil {
+		return nil, fmt.Errorf("error loading file: %w", err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("error loading file: %w", err)
+	}
+	s := Suppressions{}
+	collectSuppressions(&root, s)
+	return s, nil
+}
+
+func collectSuppressions(n *yaml.Node, s Suppressions) {
+	for _, c := range []string{n.HeadComment, n.LineComment} {
+		for _, line := range strings.Split(c, "\n") {
+			rules, ok := parseDirective(line)
+			if !ok {
+				continue
+			}
+			switch {
+			case suppressesAll(s, n.Line):
+			case len(rules) == 0:
+				s[n.Line] = []string{}
+			default:
+				s[n.Line] = append(s[n.Line], rules...)
+			}
+		}
+	}
+	for _, c := range n.Content {
+		collectSuppressions(c, s)
+	}
+}
+
+// suppressesAll reports whether line already suppresses every rule.
+func suppressesAll(s Suppressions, line int) bool {
+	rules, ok := s[line]
+	return ok && len(rules) == 0
+}
+
+// parseDirective extracts the rule IDs of an ignore comment line.
+func parseDirective(comment string) ([]string, bool) {
+	text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(comment), "#"))
+	rest, ok := strings.CutPrefix(text, ignoreDirective)
+	if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil, false
+	}
+	rules := strings.FieldsFunc(rest, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+	return rules, true
+}
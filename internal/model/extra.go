@@ -0,0 +1,144 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Extra holds the keys of an object that no field of its struct claims, such
+// as vendor x-* keys or customizations for tools the model does not know.
+// They are preserved verbatim and written after the typed fields, sorted.
+type Extra map[string]any
+
+// MarshalJSON appends the extra keys to the devcontainer.json object.
+func (dc DevContainer) MarshalJSON() ([]byte, error) {
+	type plain DevContainer
+	return marshalJSONWithExtra(plain(dc), dc.Extra)
+}
+
+// UnmarshalJSON collects keys without a field into Extra.
+func (dc *DevContainer) UnmarshalJSON(data []byte) error {
+	type plain DevContainer
+	if err := json.Unmarshal(data, (*plain)(dc)); err != nil {
+		return err
+	}
+	extra, err := unmarshalJSONExtra(data, reflect.TypeOf(*dc))
+	dc.Extra = extra
+	return err
+}
+
+// MarshalYAML appends the extra keys to the config.yaml mapping.
+func (dc DevContainer) MarshalYAML() (any, error) {
+	type plain DevContainer
+	return marshalYAMLWithExtra(plain(dc), dc.Extra)
+}
+
+// MarshalJSON appends the customizations of unknown tools.
+func (c Customizations) MarshalJSON() ([]byte, error) {
+	type plain Customizations
+	return marshalJSONWithExtra(plain(c), c.Extra)
+}
+
+// UnmarshalJSON collects the customizations of unknown tools into Extra.
+func (c *Customizations) UnmarshalJSON(data []byte) error {
+	type plain Customizations
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	extra, err := unmarshalJSONExtra(data, reflect.TypeOf(*c))
+	c.Extra = extra
+	return err
+}
+
+// MarshalYAML appends the customizations of unknown tools.
+func (c Customizations) MarshalYAML() (any, error) {
+	type plain Customizations
+	return marshalYAMLWithExtra(plain(c), c.Extra)
+}
+
+func marshalJSONWithExtra(v any, extra Extra) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	first := len(data) == 2 // "{}"
+	for _, k := range extra.keys() {
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(extra[k])
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalJSONExtra returns the keys of the JSON object data that no field
+// of struct type t claims. Like encoding/json, names match case-insensitively.
+func unmarshalJSONExtra(data []byte, t reflect.Type) (Extra, error) {
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[strings.ToLower(name)] = true
+		}
+	}
+	extra := Extra{}
+	for k, v := range all {
+		if !known[strings.ToLower(k)] {
+			extra[k] = v
+		}
+	}
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+func marshalYAMLWithExtra(v any, extra Extra) (any, error) {
+	if len(extra) == 0 {
+		return v, nil
+	}
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	for _, k := range extra.keys() {
+		var val yaml.Node
+		if err := val.Encode(extra[k]); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, &val)
+	}
+	return &n, nil
+}
+
+func (e Extra) keys() []string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
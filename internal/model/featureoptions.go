@@ -0,0 +1,127 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// FeatureOptions represents the value of an entry in features.
+// The devcontainer spec allows an object of options, a version string
+// ("1.22", shorthand for {"version": "1.22"}), or a boolean. The form it was
+// written in is kept so it round-trips.
+type FeatureOptions struct {
+	Options map[string]any `json:"-" yaml:"-"`
+	Version string         `json:"-" yaml:"-"`
+	Bool    *bool          `json:"-" yaml:"-"`
+}
+
+// FeatureObject returns FeatureOptions backed by an options object.
+func FeatureObject(opts map[string]any) FeatureOptions {
+	if opts == nil {
+		opts = map[string]any{}
+	}
+	return FeatureOptions{Options: opts}
+}
+
+// FeatureVersion returns FeatureOptions for the version string shorthand.
+func FeatureVersion(v string) FeatureOptions { return FeatureOptions{Version: v} }
+
+// FeatureBool returns FeatureOptions for the boolean shorthand.
+func FeatureBool(b bool) FeatureOptions { return FeatureOptions{Bool: &b} }
+
+// Map returns the options f stands for, expanding the version shorthand.
+func (f FeatureOptions) Map() map[string]any {
+	switch {
+	case f.Version != "":
+		return map[string]any{"version": f.Version}
+	case f.Options != nil:
+		return f.Options
+	}
+	return map[string]any{}
+}
+
+func (f FeatureOptions) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.Bool != nil:
+		return json.Marshal(*f.Bool)
+	case f.Version != "":
+		return json.Marshal(f.Version)
+	case f.Options != nil:
+		return json.Marshal(f.Options)
+	}
+	return []byte("{}"), nil
+}
+
+func (f *FeatureOptions) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*f = FeatureBool(b)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = FeatureVersion(s)
+		return nil
+	}
+	var opts map[string]any
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return fmt.Errorf("feature value must be a version string, a boolean, or an object of options: %w", err)
+	}
+	*f = FeatureObject(opts)
+	return nil
+}
+
+// JSONSchema implements jsonschema.JSONSchema, declaring a feature's value
+// as a version string, a boolean, or an object of free-form options.
+func (FeatureOptions) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{OneOf: []*jsonschema.Schema{
+		{Type: "string"},
+		{Type: "boolean"},
+		{Type: "object"},
+	}}
+}
+
+// MarshalYAML implements yaml.Marshaler so yaml.Marshal produces the correct output.
+func (f FeatureOptions) MarshalYAML() (any, error) {
+	switch {
+	case f.Bool != nil:
+		return *f.Bool, nil
+	case f.Version != "":
+		return f.Version, nil
+	case f.Options != nil:
+		return f.Options, nil
+	}
+	return map[string]any{}, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for direct yaml.v3 decoding.
+func (f *FeatureOptions) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Tag == "!!bool" {
+			var b bool
+			if err := value.Decode(&b); err != nil {
+				return err
+			}
+			*f = FeatureBool(b)
+			return nil
+		}
+		if value.Tag == "!!null" {
+			*f = FeatureObject(nil)
+			return nil
+		}
+		*f = FeatureVersion(value.Value)
+		return nil
+	case yaml.MappingNode:
+		var opts map[string]any
+		if err := value.Decode(&opts); err != nil {
+			return err
+		}
+		*f = FeatureObject(opts)
+		return nil
+	}
+	return fmt.Errorf("feature value must be a version string, a boolean, or an object of options")
+}
@@ -4,14 +4,14 @@
 package model
 
 type DevContainer struct {
-	Schema            string       `json:"$schema,omitempty" yaml:"$schema,omitempty" jsonschema_description:"URL of the JSON schema that describes the format of this file."`
-	Name              string       `json:"name,omitempty" yaml:"name,omitempty" validate:"required" jsonschema:"required" jsonschema_description:"Name of the dev container."`
-	Image             string       `json:"image,omitempty" yaml:"image,omitempty" jsonschema_description:"Docker image to use for the dev container."`
-	Build             *BuildConfig `json:"build,omitempty" yaml:"build,omitempty" validate:"omitempty" jsonschema_description:"Configuration for building the image."`
-	DockerFile        string       `json:"dockerFile,omitempty" yaml:"dockerFile,omitempty" jsonschema_description:"Deprecated: legacy path to the Dockerfile. Use build.dockerfile instead."`
-	DockerComposeFile []string     `json:"dockerComposeFile,omitempty" yaml:"dockerComposeFile,omitempty" jsonschema_description:"List of Docker Compose files to use."`
-	Service           string       `json:"service,omitempty" yaml:"service,omitempty" validate:"required_with=DockerComposeFile" jsonschema_description:"Specific service to run from Docker Compose."`
-	RunServices       []string     `json:"runServices,omitempty" yaml:"runServices,omitempty" jsonschema_description:"Docker Compose services to start automatically alongside the dev container service."`
+	Schema            string         `json:"$schema,omitempty" yaml:"$schema,omitempty" jsonschema_description:"URL of the JSON schema that describes the format of this file."`
+	Name              string         `json:"name,omitempty" yaml:"name,omitempty" validate:"required" jsonschema:"required" jsonschema_description:"Name of the dev container."`
+	Image             string         `json:"image,omitempty" yaml:"image,omitempty" jsonschema_description:"Docker image to use for the dev container."`
+	Build             *BuildConfig   `json:"build,omitempty" yaml:"build,omitempty" validate:"omitempty" jsonschema_description:"Configuration for building the image."`
+	DockerFile        string         `json:"dockerFile,omitempty" yaml:"dockerFile,omitempty" jsonschema_description:"Deprecated: legacy path to the Dockerfile. Use build.dockerfile instead."`
+	DockerComposeFile *StringOrSlice `json:"dockerComposeFile,omitempty" yaml:"dockerComposeFile,omitempty" jsonschema_description:"Docker Compose file, or list of files, to use. Paths are relative to the devcontainer.json."`
+	Service           string         `json:"service,omitempty" yaml:"service,omitempty" validate:"required_with=DockerComposeFile" jsonschema_description:"Specific service to run from Docker Compose."`
+	RunServices       []string       `json:"runServices,omitempty" yaml:"runServices,omitempty" jsonschema_description:"Docker Compose services to start automatically alongside the dev container service."`
 
 	WorkspaceFolder     string `json:"workspaceFolder,omitempty" yaml:"workspaceFolder,omitempty" jsonschema_description:"Path to the workspace folder inside the container."`
 	WorkspaceMount      string `json:"workspaceMount,omitempty" yaml:"workspaceMount,omitempty" jsonschema_description:"Mount type for the workspace folder."`
@@ -21,12 +21,12 @@ type DevContainer struct {
 	UserEnvProbe        string `json:"userEnvProbe,omitempty" yaml:"userEnvProbe,omitempty" validate:"omitempty,oneof=none loginShell loginInteractiveShell interactiveShell" jsonschema_description:"Shell type used to probe user environment variables."`
 
 	// Environment variables
-	ContainerEnv map[string]string `json:"containerEnv,omitempty" yaml:"containerEnv,omitempty" jsonschema_description:"Environment variables to set in the container."`
-	RemoteEnv    map[string]string `json:"remoteEnv,omitempty" yaml:"remoteEnv,omitempty" jsonschema_description:"Environment variables for remote connections (like SSH)."`
+	ContainerEnv map[string]string  `json:"containerEnv,omitempty" yaml:"containerEnv,omitempty" validate:"omitempty,dive,keys,env_name,endkeys" jsonschema_description:"Environment variables to set in the container."`
+	RemoteEnv    map[string]*string `json:"remoteEnv,omitempty" yaml:"remoteEnv,omitempty" validate:"omitempty,dive,keys,env_name,endkeys" jsonschema_description:"Environment variables for remote connections (like SSH). A null value unsets the variable."`
 
-	ForwardPorts         []any                      `json:"forwardPorts,omitempty" yaml:"forwardPorts,omitempty" jsonschema_description:"Ports that are forwarded from the container to the local machine. Can be an integer port number, or a string of the format \"host:port_number\""`
-	AppPort              []any                      `json:"appPort,omitempty" yaml:"appPort,omitempty" jsonschema_description:"Legacy: ports to publish from the container. Prefer forwardPorts instead."`
-	PortsAttributes      map[string]*PortAttributes `json:"portsAttributes,omitempty" yaml:"portsAttributes,omitempty" validate:"omitempty" jsonschema_description:"Additional attributes for forwarded ports."`
+	ForwardPorts         []PortSpec                 `json:"forwardPorts,omitempty" yaml:"forwardPorts,omitempty" validate:"omitempty,dive,forward_port" jsonschema_description:"Ports that are forwarded from the container to the local machine. Can be an integer port number, or a string of the format \"host:port_number\" (for Docker Compose, \"service:port_number\")."`
+	AppPort              []PortSpec                 `json:"appPort,omitempty" yaml:"appPort,omitempty" validate:"omitempty,dive,app_port" jsonschema_description:"Legacy: ports to publish from the container. Prefer forwardPorts instead."`
+	PortsAttributes      map[string]*PortAttributes `json:"portsAttributes,omitempty" yaml:"portsAttributes,omitempty" validate:"omitempty,dive,keys,port_attributes_key,endkeys" jsonschema_description:"Additional attributes for forwarded ports."`
 	OtherPortsAttributes *PortAttributes            `json:"otherPortsAttributes,omitempty" yaml:"otherPortsAttributes,omitempty" validate:"omitempty" jsonschema_description:"Default attributes applied to all forwarded ports not defined in portsAttributes."`
 	Mounts               []MountOrString            `json:"mounts,omitempty" yaml:"mounts,omitempty" validate:"omitempty,dive" jsonschema_description:"Mount points inside the container. Each entry can be a Mount object or a Docker --mount string."`
 
@@ -42,7 +42,7 @@ type DevContainer struct {
 
 	HostRequirements            *HostRequirements         `json:"hostRequirements,omitempty" yaml:"hostRequirements,omitempty" validate:"omitempty" jsonschema_description:"Minimum host hardware requirements for the dev container."`
 	OverrideFeatureInstallOrder []string                  `json:"overrideFeatureInstallOrder,omitempty" yaml:"overrideFeatureInstallOrder,omitempty" jsonschema_description:"Order to install features inside the container, overriding defaults."`
-	Features                    map[string]map[string]any `json:"features,omitempty" yaml:"features,omitempty" jsonschema_description:"Features to install in the container and their options."`
+	Features                    map[string]FeatureOptions `json:"features,omitempty" yaml:"features,omitempty" validate:"omitempty,dive" jsonschema_description:"Features to install in the container. Each value is an object of options, a version string, or true."`
 
 	InitializeCommand    *CommandValue `json:"initializeCommand,omitempty" yaml:"initializeCommand,omitempty" jsonschema_description:"Command to run on the host before the container is created or started. Can be a string, an array of strings, or a named command object."`
 	OnCreateCommand      *CommandValue `json:"onCreateCommand,omitempty" yaml:"onCreateCommand,omitempty" jsonschema_description:"Command to run after the container is created. Can be a string, an array of strings, or a named command object."`
@@ -55,9 +55,13 @@ type DevContainer struct {
 	Watch          *WatchConfig    `json:"watch,omitempty" yaml:"watch,omitempty" validate:"omitempty" jsonschema_description:"Configuration for files/processes to watch for restarts."`
 	Customizations *Customizations `json:"customizations,omitempty" yaml:"customizations,omitempty" validate:"omitempty" jsonschema_description:"Editor/IDE customizations inside the container."`
 
-	Secrets map[string]Secret `json:"secrets,omitempty" yaml:"secrets,omitempty" validate:"omitempty" jsonschema_description:"Secrets to pass to the container."`
+	Secrets map[string]Secret `json:"secrets,omitempty" yaml:"secrets,omitempty" validate:"omitempty,dive" jsonschema_description:"Secrets to pass to the container."`
 
-	ShutdownAction string `json:"shutdownAction,omitempty" yaml:"shutdownAction,omitempty" validate:"omitempty,oneof=none stopContainer" jsonschema_description:"Action to take when the container is stopped. Use none or stopContainer (stopCompose is only valid in compose variants)."`
+	ShutdownAction string `json:"shutdownAction,omitempty" yaml:"shutdownAction,omitempty" validate:"omitempty,oneof=none stopContainer stopCompose" jsonschema_description:"Action to take when the container is stopped: none, stopContainer, or stopCompose with dockerComposeFile."`
+
+	// Extra holds vendor x-* keys, and with --strict=false any other unknown
+	// top-level key, copied verbatim into devcontainer.json.
+	Extra Extra `json:"-" yaml:"-" koanf:"-"`
 }
 
 // BuildConfig defines parameters for building a dev container image.
@@ -66,7 +70,7 @@ type BuildConfig struct {
 	Context    string            `json:"context,omitempty" yaml:"context,omitempty" validate:"required" jsonschema:"required,default=." jsonschema_description:"Build context directory."`
 	Args       map[string]string `json:"args,omitempty" yaml:"args,omitempty" validate:"omitempty" jsonschema_description:"Build arguments as key-value pairs."`
 	Target     string            `json:"target,omitempty" yaml:"target,omitempty" validate:"omitempty" jsonschema_description:"Target stage for multi-stage Docker builds."`
-	CacheFrom  []string          `json:"cacheFrom,omitempty" yaml:"cacheFrom,omitempty" validate:"omitempty" jsonschema_description:"List of images to cache from."`
+	CacheFrom  *StringOrSlice    `json:"cacheFrom,omitempty" yaml:"cacheFrom,omitempty" validate:"omitempty" jsonschema_description:"Image, or list of images, to use as a build cache."`
 	Options    []string          `json:"options,omitempty" yaml:"options,omitempty" validate:"omitempty" jsonschema_description:"Additional CLI options passed to docker build (e.g. --no-cache)."`
 }
 
@@ -98,6 +102,10 @@ type Customizations struct {
 	VSCode     *VSCodeCustomization     `json:"vscode,omitempty" yaml:"vscode,omitempty" validate:"omitempty" jsonschema_description:"VS Code specific customizations."`
 	Codespaces *CodespacesCustomization `json:"codespaces,omitempty" yaml:"codespaces,omitempty" validate:"omitempty" jsonschema_description:"Codespaces specific customizations."`
 	JetBrains  *JetBrainsCustomization  `json:"jetbrains,omitempty" yaml:"jetbrains,omitempty" validate:"omitempty" jsonschema_description:"JetBrains IDE specific customizations."`
+
+	// Extra holds the customizations of tools the model does not know, such
+	// as devpod or gitpod, copied verbatim into devcontainer.json.
+	Extra Extra `json:"-" yaml:"-" koanf:"-"`
 }
 
 // VSCodeCustomization defines VS Code-specific settings.
@@ -110,6 +118,15 @@ type VSCodeCustomization struct {
 type CodespacesCustomization struct {
 	Settings   map[string]any `json:"settings,omitempty" yaml:"settings,omitempty" validate:"omitempty" jsonschema_description:"Key-value settings for Codespaces."`
 	Extensions []string       `json:"extensions,omitempty" yaml:"extensions,omitempty" validate:"omitempty" jsonschema_description:"List of Codespaces extensions to install."`
+
+	Repositories map[string]CodespacesRepository `json:"repositories,omitempty" yaml:"repositories,omitempty" validate:"omitempty,dive,keys,codespaces_repository,endkeys" jsonschema_description:"Other repositories the codespace can access, keyed by owner/repo or owner/*."`
+	OpenFiles    []string                        `json:"openFiles,omitempty" yaml:"openFiles,omitempty" validate:"omitempty,dive,required" jsonschema_description:"Files to open when the codespace starts, relative to the repository root."`
+}
+
+// CodespacesRepository defines the permissions a codespace is granted on
+// another repository.
+type CodespacesRepository struct {
+	Permissions *Permissions `json:"permissions,omitempty" yaml:"permissions,omitempty" validate:"required" jsonschema:"required" jsonschema_description:"\"read-all\", \"write-all\", or permissions by name (contents, issues, pull_requests, ...), each read or write."`
 }
 
 // JetBrainsCustomization defines JetBrains IDE configuration.
@@ -119,7 +136,7 @@ type JetBrainsCustomization struct {
 
 // Secret defines a reusable secret for builds or runtime.
 type Secret struct {
-	Description      string `json:"description,omitempty" yaml:"description,omitempty" validate:"required" jsonschema_description:"Human-readable description of the secret."`
+	Description      string `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"Human-readable description of the secret."`
 	DocumentationURL string `json:"documentationUrl,omitempty" yaml:"documentationUrl,omitempty" validate:"omitempty,url" jsonschema_description:"URL pointing to documentation for this secret."`
 }
 
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MountOption is an option of a Docker --mount string other than type,
+// source, target and readonly, e.g. consistency=cached or volume-opt=o=bind.
+// Value is empty for flags written without one, such as volume-nocopy.
+type MountOption struct {
+	Key   string
+	Value string
+}
+
+// MountSpec is the structured form of a Docker --mount string. The embedded
+// Mount carries the fields shared with the object form, so both forms are
+// validated by the same rules; anything else is kept in Options, in order.
+type MountSpec struct {
+	Mount
+	Options []MountOption
+}
+
+// mountOptionValues lists the options accepted in a --mount string. A nil
+// entry accepts any value.
+var mountOptionValues = map[string][]string{
+	"consistency":       {"default", "consistent", "cached", "delegated"},
+	"bind-propagation":  {"shared", "slave", "private", "rshared", "rslave", "rprivate"},
+	"bind-nonrecursive": nil,
+	"bind-recursive":    {"enabled", "disabled", "writable", "readonly"},
+	"volume-driver":     nil,
+	"volume-subpath":    nil,
+	"volume-nocopy":     nil,
+	"volume-opt":        nil,
+	"tmpfs-size":        nil,
+	"tmpfs-mode":        nil,
+}
+
+// ParseMountString parses a Docker --mount string such as
+// "source=/x,target=/y,type=bind,consistency=cached". The aliases src, dst,
+// destination and ro are accepted. Unknown options, bad option values and
+// options of another mount type are errors; a missing type or target is left
+// for validation to report.
+func ParseMountString(s string) (MountSpec, error) {
+	r := csv.NewReader(strings.NewReader(s))
+	fields, err := r.Read()
+	if err != nil {
+		return MountSpec{}, fmt.Errorf("invalid mount string: %w", err)
+	}
+
+	var spec MountSpec
+	for _, field := range fields {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		key = strings.ToLower(key)
+		switch key {
+		case "":
+			return MountSpec{}, fmt.Errorf("empty option in mount string")
+		case "type":
+			spec.Type = value
+		case "source", "src":
+			spec.Source = value
+		case "target", "destination", "dst":
+			spec.Target = value
+		case "readonly", "ro":
+			switch strings.ToLower(value) {
+			case "", "true", "1":
+				spec.ReadOnly = true
+			case "false", "0":
+				spec.ReadOnly = false
+			default:
+				return MountSpec{}, fmt.Errorf("invalid value '%s' for %s", value, key)
+			}
+		default:
+			allowed, ok := mountOptionValues[key]
+			if !ok {
+				return MountSpec{}, fmt.Errorf("unknown mount option '%s'", key)
+			}
+			if allowed != nil && !slices.Contains(allowed, value) {
+				return MountSpec{}, fmt.Errorf("invalid value '%s' for %s (want one of: %s)", value, key, strings.Join(allowed, ", "))
+			}
+			spec.Options = append(spec.Options, MountOption{Key: key, Value: value})
+		}
+	}
+
+	for _, o := range spec.Options {
+		if kind, _, ok := strings.Cut(o.Key, "-"); ok && spec.Type != "" && kind != spec.Type {
+			return MountSpec{}, fmt.Errorf("option '%s' cannot be used with a %s mount", o.Key, spec.Type)
+		}
+	}
+	return spec, nil
+}
+
+// String renders s in the canonical --mount form: source, target and type
+// first, then readonly and the remaining options in their original order.
+// Fields containing commas or quotes are quoted.
+func (s MountSpec) String() string {
+	var fields []string
+	if s.Source != "" {
+		fields = append(fields, "source="+s.Source)
+	}
+	if s.Target != "" {
+		fields = append(fields, "target="+s.Target)
+	}
+	if s.Type != "" {
+		fields = append(fields, "type="+s.Type)
+	}
+	if s.ReadOnly {
+		fields = append(fields, "readonly")
+	}
+	for _, o := range s.Options {
+		if o.Value == "" {
+			fields = append(fields, o.Key)
+			continue
+		}
+		fields = append(fields, o.Key+"="+o.Value)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Spec returns the structured form of m, parsing the string form.
+func (m MountOrString) Spec() (MountSpec, error) {
+	if m.Str != "" {
+		return ParseMountString(m.Str)
+	}
+	if m.Mount == nil {
+		return MountSpec{}, nil
+	}
+	return MountSpec{Mount: *m.Mount}, nil
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Permissions represents the permissions of an entry in
+// customizations.codespaces.repositories. The spec allows the shorthand
+// strings "read-all" and "write-all", or a map of permission names to "read"
+// or "write". The form it was written in is kept so it round-trips.
+type Permissions struct {
+	All    string            `json:"-" yaml:"-" validate:"omitempty,oneof=read-all write-all"`
+	Scopes map[string]string `json:"-" yaml:"-" validate:"omitempty,dive,keys,oneof=actions checks contents deployments discussions issues packages pages pull_requests repository_projects statuses workflows,endkeys,oneof=read write"` //nolint:lll
+}
+
+// permissionScopes lists the permission names accepted in Scopes, as in its
+// oneof validation.
+var permissionScopes = []any{
+	"actions", "checks", "contents", "deployments", "discussions", "issues", "packages",
+	"pages", "pull_requests", "repository_projects", "statuses", "workflows",
+}
+
+// PermissionsAll returns Permissions for a shorthand such as "read-all".
+func PermissionsAll(s string) Permissions { return Permissions{All: s} }
+
+// PermissionScopes returns Permissions granted per permission name.
+func PermissionScopes(m map[string]string) Permissions { return Permissions{Scopes: m} }
+
+// PermissionsAllPtr is a convenience constructor returning a pointer.
+func PermissionsAllPtr(s string) *Permissions { v := PermissionsAll(s); return &v }
+
+// PermissionScopesPtr is a convenience constructor returning a pointer.
+func PermissionScopesPtr(m map[string]string) *Permissions { v := PermissionScopes(m); return &v }
+
+// JSONSchema implements jsonschema.JSONSchema, declaring permissions as a
+// shorthand string or an object of permission names, each read or write.
+func (Permissions) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{OneOf: []*jsonschema.Schema{
+		{Type: "string", Enum: []any{"read-all", "write-all"}},
+		{
+			Type:                 "object",
+			PropertyNames:        &jsonschema.Schema{Enum: permissionScopes},
+			AdditionalProperties: &jsonschema.Schema{Type: "string", Enum: []any{"read", "write"}},
+		},
+	}}
+}
+
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	if p.All != "" {
+		return json.Marshal(p.All)
+	}
+	if p.Scopes == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p.Scopes)
+}
+
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*p = PermissionsAll(s)
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("permissions must be \"read-all\", \"write-all\", or an object of permission names: %w", err)
+	}
+	*p = PermissionScopes(m)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler so yaml.Marshal produces the correct output.
+func (p Permissions) MarshalYAML() (any, error) {
+	if p.All != "" {
+		return p.All, nil
+	}
+	if p.Scopes == nil {
+		return map[string]string{}, nil
+	}
+	return p.Scopes, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for direct yaml.v3 decoding.
+func (p *Permissions) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*p = PermissionsAll(value.Value)
+		return nil
+	case yaml.MappingNode:
+		var m map[string]string
+		if err := value.Decode(&m); err != nil {
+			return err
+		}
+		*p = PermissionScopes(m)
+		return nil
+	}
+	return fmt.Errorf("permissions must be \"read-all\", \"write-all\", or an object of permission names")
+}
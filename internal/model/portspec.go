@@ -0,0 +1,151 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// PortSpec represents an element of forwardPorts or appPort.
+// The devcontainer spec allows a port number, or a string naming a port on
+// another host: "host:port", or for Docker Compose "service:port". appPort
+// additionally accepts Docker publish specs such as "8080:3000".
+type PortSpec struct {
+	Number *int   `json:"-" yaml:"-"`
+	Str    string `json:"-" yaml:"-"`
+}
+
+// PortNumber returns a PortSpec for a port of the container itself.
+func PortNumber(n int) PortSpec { return PortSpec{Number: &n} }
+
+// PortString returns a PortSpec backed by a "host:port" string.
+func PortString(s string) PortSpec { return PortSpec{Str: s} }
+
+// String returns the port as written: the number, or the string form.
+func (p PortSpec) String() string {
+	if p.Number != nil {
+		return strconv.Itoa(*p.Number)
+	}
+	return p.Str
+}
+
+// hostNameRe matches a host name, IP address or Compose service name.
+var hostNameRe = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+
+// Port returns the host and port p refers to. host is empty for a plain port
+// number. Ports must be within 1-65535 and hosts must be valid names.
+func (p PortSpec) Port() (host string, port int, err error) {
+	s := p.String()
+	if i := strings.LastIndexByte(s, ':'); i >= 0 {
+		host, s = s[:i], s[i+1:]
+		if !hostNameRe.MatchString(host) {
+			return "", 0, fmt.Errorf("invalid host '%s'", host)
+		}
+	}
+	port, err = ParsePortNumber(s)
+	return host, port, err
+}
+
+// PublishedPort returns the container port of an appPort entry, which may be
+// a Docker publish spec: [ip:][hostPort:]containerPort[/tcp|/udp].
+func (p PortSpec) PublishedPort() (int, error) {
+	s := p.String()
+	if base, proto, ok := strings.Cut(s, "/"); ok {
+		if proto != "tcp" && proto != "udp" {
+			return 0, fmt.Errorf("invalid protocol '%s'", proto)
+		}
+		s = base
+	}
+	parts := strings.Split(s, ":")
+	switch len(parts) {
+	case 1:
+	case 2:
+		if _, err := ParsePortNumber(parts[0]); err != nil {
+			return 0, err
+		}
+	case 3:
+		if !hostNameRe.MatchString(parts[0]) {
+			return 0, fmt.Errorf("invalid host '%s'", parts[0])
+		}
+		if parts[1] != "" {
+			if _, err := ParsePortNumber(parts[1]); err != nil {
+				return 0, err
+			}
+		}
+	default:
+		return 0, fmt.Errorf("invalid publish spec '%s'", p.String())
+	}
+	return ParsePortNumber(parts[len(parts)-1])
+}
+
+// ParsePortNumber parses a port number within 1-65535.
+func ParsePortNumber(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port '%s'", s)
+	}
+	if n < 1 || n > 65535 {
+		return 0, fmt.Errorf("port %d is out of range 1-65535", n)
+	}
+	return n, nil
+}
+
+// JSONSchema implements jsonschema.JSONSchema, declaring a port as either a
+// port number or a "host:port" string.
+func (PortSpec) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{OneOf: []*jsonschema.Schema{
+		{Type: "integer", Minimum: json.Number("1"), Maximum: json.Number("65535")},
+		{Type: "string"},
+	}}
+}
+
+func (p PortSpec) MarshalJSON() ([]byte, error) {
+	if p.Number != nil {
+		return json.Marshal(*p.Number)
+	}
+	return json.Marshal(p.Str)
+}
+
+func (p *PortSpec) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		p.Number = &n
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("port must be a number or a string: %w", err)
+	}
+	p.Str = s
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler so yaml.Marshal produces the correct output.
+func (p PortSpec) MarshalYAML() (any, error) {
+	if p.Number != nil {
+		return *p.Number, nil
+	}
+	return p.Str, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for direct yaml.v3 decoding.
+func (p *PortSpec) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("port must be a number or a string")
+	}
+	if value.Tag == "!!int" {
+		var n int
+		if err := value.Decode(&n); err != nil {
+			return err
+		}
+		p.Number = &n
+		return nil
+	}
+	p.Str = value.Value
+	return nil
+}
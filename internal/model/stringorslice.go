@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lucasassuncao/yedit/schema"
+)
+
+// StringOrSlice represents a field the devcontainer spec allows as either a
+// single string or an array of strings, such as dockerComposeFile and
+// build.cacheFrom. The form it was written in is kept, so a scalar stays a
+// scalar through Parse and WriteFile.
+type StringOrSlice struct {
+	Items  []string `json:"-" yaml:"-"`
+	Scalar bool     `json:"-" yaml:"-"`
+}
+
+// SingleString returns a StringOrSlice written as a plain string.
+func SingleString(s string) StringOrSlice { return StringOrSlice{Items: []string{s}, Scalar: true} }
+
+// StringSlice returns a StringOrSlice written as an array.
+func StringSlice(s []string) StringOrSlice { return StringOrSlice{Items: s} }
+
+// SingleStringPtr is a convenience constructor returning a pointer.
+func SingleStringPtr(s string) *StringOrSlice { v := SingleString(s); return &v }
+
+// StringSlicePtr is a convenience constructor returning a pointer.
+func StringSlicePtr(s []string) *StringOrSlice { v := StringSlice(s); return &v }
+
+// Values returns the strings of v in either form; nil when v is nil.
+func (v *StringOrSlice) Values() []string {
+	if v == nil {
+		return nil
+	}
+	return v.Items
+}
+
+// JSONSchema implements jsonschema.JSONSchema, declaring the value as either
+// a string or an array of strings.
+func (StringOrSlice) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{OneOf: []*jsonschema.Schema{
+		{Type: "string"},
+		{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+	}}
+}
+
+// YeditSchema implements yedit/schema.Provider. The value has no fields of
+// its own, so the editor edits it, like a plain list of strings, through the
+// raw YAML pane.
+func (StringOrSlice) YeditSchema() []schema.FieldDef {
+	return []schema.FieldDef{}
+}
+
+func (v StringOrSlice) MarshalJSON() ([]byte, error) {
+	if v.Scalar && len(v.Items) == 1 {
+		return json.Marshal(v.Items[0])
+	}
+	if v.Items == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal(v.Items)
+}
+
+func (v *StringOrSlice) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = SingleString(s)
+		return nil
+	}
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("value must be a string or an array of strings: %w", err)
+	}
+	*v = StringSlice(items)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler so yaml.Marshal produces the correct output.
+func (v StringOrSlice) MarshalYAML() (any, error) {
+	if v.Scalar && len(v.Items) == 1 {
+		return v.Items[0], nil
+	}
+	return v.Items, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for direct yaml.v3 decoding.
+func (v *StringOrSlice) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*v = SingleString(value.Value)
+		return nil
+	case yaml.SequenceNode:
+		var items []string
+		if err := value.Decode(&items); err != nil {
+			return err
+		}
+		*v = StringSlice(items)
+		return nil
+	}
+	return fmt.Errorf("value must be a string or a list of strings")
+}
@@ -0,0 +1,184 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+	"github.com/lucasassuncao/devcontainerwizard/internal/diagnostic"
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+// Rule IDs of policy violations, one per policy field.
+const (
+	RuleRegistry   = "policy/allowed-registries"
+	RuleFeature    = "policy/allowed-features"
+	RuleCapability = "policy/banned-capabilities"
+	RuleHostLimits = "policy/max-host-requirements"
+	RuleExtension  = "policy/required-extensions"
+)
+
+const (
+	defaultRegistry = "docker.io"
+	officialLibrary = "library/"
+)
+
+// Evaluate checks dc against the policy and returns one error diagnostic per
+// violation, positioned using pos.
+func (p *Policy) Evaluate(dc *model.DevContainer, file string, pos devcontainer.Positions) []diagnostic.Diagnostic {
+	var out []diagnostic.Diagnostic
+	add := func(rule, yamlPath, msg string) {
+		d := diagnostic.Diagnostic{
+			RuleID:   rule,
+			Severity: diagnostic.SeverityError,
+			Message:  msg,
+			File:     file,
+			Path:     yamlPath,
+		}
+		pos.Place(&d)
+		out = append(out, d)
+	}
+
+	if len(p.AllowedRegistries) > 0 && dc.Image != "" && !p.registryAllowed(dc.Image) {
+		add(RuleRegistry, "image", fmt.Sprintf("Image '%s' is not from an allowed registry (allowed: %s).",
+			dc.Image, strings.Join(p.AllowedRegistries, ", ")))
+	}
+
+	if len(p.AllowedFeatures) > 0 {
+		for _, id := range devcontainer.SortedKeys(dc.Features) {
+			if !p.featureAllowed(id) {
+				add(RuleFeature, "features."+strconv.Quote(id), fmt.Sprintf("Feature '%s' is not from an allowed source (allowed: %s).",
+					id, strings.Join(p.AllowedFeatures, ", ")))
+			}
+		}
+	}
+
+	for i, c := range dc.CapAdd {
+		if p.capabilityBanned(c) {
+			add(RuleCapability, "capAdd["+strconv.Itoa(i)+"]", fmt.Sprintf("Capability '%s' is banned by policy.", c))
+		}
+	}
+
+	if l, hr := p.MaxHostRequirements, dc.HostRequirements; l != nil && hr != nil {
+		if l.CPUs > 0 && hr.CPUs > l.CPUs {
+			add(RuleHostLimits, "hostRequirements.cpus", fmt.Sprintf("hostRequirements.cpus is %d; the policy allows at most %d.", hr.CPUs, l.CPUs))
+		}
+		for _, s := range []struct{ name, got, limit string }{
+			{"memory", hr.Memory, l.Memory},
+			{"storage", hr.Storage, l.Storage},
+		} {
+			over, err := exceeds(s.got, s.limit)
+			switch {
+			case err != nil:
+				add(RuleHostLimits, "hostRequirements."+s.name, fmt.Sprintf("hostRequirements.%s cannot be checked against the policy limit of %s: %v.", s.name, s.limit, err))
+			case over:
+				add(RuleHostLimits, "hostRequirements."+s.name, fmt.Sprintf("hostRequirements.%s is %s; the policy allows at most %s.", s.name, s.got, s.limit))
+			}
+		}
+	}
+
+	var installed []string
+	if c := dc.Customizations; c != nil && c.VSCode != nil {
+		installed = c.VSCode.Extensions
+	}
+	for _, ext := range p.RequiredExtensions {
+		if !slices.ContainsFunc(installed, func(e string) bool { return strings.EqualFold(e, ext) }) {
+			add(RuleExtension, "customizations.vscode.extensions", fmt.Sprintf("Required extension '%s' is missing from customizations.vscode.extensions.", ext))
+		}
+	}
+	return out
+}
+
+// registryAllowed reports whether image matches an allowed registry host or
+// repository prefix.
+func (p *Policy) registryAllowed(image string) bool {
+	ref := normalizeImage(image)
+	for _, allowed := range p.AllowedRegistries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if strings.HasPrefix(ref, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImage prefixes image with docker.io (and library/ for official
+// images) when it has no registry host, so that "ubuntu" and
+// "docker.io/library/ubuntu" are treated alike.
+func normalizeImage(image string) string {
+	first, rest, ok := strings.Cut(image, "/")
+	if ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !ok {
+		return defaultRegistry + "/" + officialLibrary + first
+	}
+	return defaultRegistry + "/" + first + "/" + rest
+}
+
+// featureAllowed matches a feature ID, with its version or digest removed,
+// against the allowed patterns.
+func (p *Policy) featureAllowed(id string) bool {
+	bare := devcontainer.BareFeatureID(id)
+	for _, pat := range p.AllowedFeatures {
+		if ok, _ := path.Match(pat, bare); ok {
+			return true
+		}
+		if ok, _ := path.Match(pat, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) capabilityBanned(c string) bool {
+	name := devcontainer.CapabilityName(c)
+	for _, b := range p.BannedCapabilities {
+		if devcontainer.CapabilityName(b) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// exceeds reports whether size got is larger than limit. A got that cannot
+// be parsed is returned as an error rather than passing the check; limit has
+// already been checked by Load.
+func exceeds(got, limit string) (bool, error) {
+	if got == "" || limit == "" {
+		return false, nil
+	}
+	g, err := parseSize(got)
+	if err != nil {
+		return false, err
+	}
+	l, err := parseSize(limit)
+	if err != nil {
+		return false, err
+	}
+	return g > l, nil
+}
+
+// parseSize parses sizes such as "8gb", "512mb" or "1tb" into bytes.
+func parseSize(s string) (float64, error) {
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"tb", 1 << 40}, {"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10}, {"b", 1},
+	}
+	v := strings.ToLower(strings.TrimSpace(s))
+	for _, u := range units {
+		if num, ok := strings.CutSuffix(v, u.suffix); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid size '%s'", s)
+			}
+			return n * u.factor, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid size '%s' (want a number followed by tb, gb, mb or kb)", s)
+}
@@ -0,0 +1,112 @@
+// Package policy enforces organisation guardrails on a parsed dev container
+// config: which registries and feature sources may be used, which
+// capabilities are banned, how much host hardware may be requested and which
+// extensions must be installed.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/devcontainer"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVar names the environment variable that points at a policy file when
+// --policy is not given.
+const EnvVar = "DEVCONTAINERWIZARD_POLICY"
+
+// DefaultFile is the policy file discovered in the repository root.
+const DefaultFile = ".devcontainerwizard-policy.yaml"
+
+// Policy is the content of a policy file. Empty fields impose no constraint.
+type Policy struct {
+	// AllowedRegistries lists registry hosts ("mcr.microsoft.com") or
+	// repository prefixes ("ghcr.io/acme") that image may come from. Images
+	// without a registry resolve to docker.io.
+	AllowedRegistries []string `yaml:"allowedRegistries"`
+	// AllowedFeatures lists glob patterns ("ghcr.io/devcontainers/features/*")
+	// matched against each feature ID without its version.
+	AllowedFeatures []string `yaml:"allowedFeatures"`
+	// BannedCapabilities lists capabilities that may not appear in capAdd.
+	BannedCapabilities []string `yaml:"bannedCapabilities"`
+	// MaxHostRequirements caps the values of hostRequirements.
+	MaxHostRequirements *HostLimits `yaml:"maxHostRequirements"`
+	// RequiredExtensions lists VS Code extensions that must be installed.
+	RequiredExtensions []string `yaml:"requiredExtensions"`
+
+	// File is the path the policy was loaded from.
+	File string `yaml:"-"`
+}
+
+// HostLimits caps hostRequirements. Memory and storage use the same notation
+// as the spec ("8gb", "512mb").
+type HostLimits struct {
+	CPUs    int    `yaml:"cpus"`
+	Memory  string `yaml:"memory"`
+	Storage string `yaml:"storage"`
+}
+
+// Load reads and checks the policy file at path.
+func Load(file string) (*Policy, error) {
+	data, err := os.ReadFile(file) // #nosec G304 -- user-supplied policy path
+	if err != nil {
+		return nil, fmt.Errorf("error loading policy: %w", err)
+	}
+
+	p := &Policy{File: file}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error loading policy %s: %w", file, err)
+	}
+
+	for _, pat := range p.AllowedFeatures {
+		if _, err := path.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("policy %s: invalid allowedFeatures pattern '%s': %w", file, pat, err)
+		}
+	}
+	if l := p.MaxHostRequirements; l != nil {
+		for name, v := range map[string]string{"memory": l.Memory, "storage": l.Storage} {
+			if _, err := parseSize(v); v != "" && err != nil {
+				return nil, fmt.Errorf("policy %s: maxHostRequirements.%s: %w", file, name, err)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Resolve finds the policy file to enforce: flag if set, else the file named
+// by EnvVar, else DefaultFile in the repository root containing dir (returned
+// relative to dir). It returns "" when no policy applies.
+func Resolve(flag, dir string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		return env, nil
+	}
+	root := devcontainer.RepositoryRoot(dir)
+	if root == "" {
+		root = dir
+	}
+	candidate := filepath.Join(root, DefaultFile)
+	if abs, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(abs, candidate); err == nil {
+			candidate = filepath.Join(dir, rel)
+		}
+	}
+	if _, err := os.Stat(candidate); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error loading policy: %w", err)
+	}
+	return candidate, nil
+}
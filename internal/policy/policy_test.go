@@ -0,0 +1,138 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasassuncao/devcontainerwizard/internal/model"
+)
+
+func TestEvaluate(t *testing.T) {
+	p := &Policy{
+		AllowedRegistries:   []string{"mcr.microsoft.com", "ghcr.io/acme/"},
+		AllowedFeatures:     []string{"ghcr.io/devcontainers/features/*"},
+		BannedCapabilities:  []string{"CAP_SYS_ADMIN"},
+		MaxHostRequirements: &HostLimits{CPUs: 8, Memory: "16gb", Storage: "64gb"},
+		RequiredExtensions:  []string{"EditorConfig.EditorConfig", "ms-python.python"},
+	}
+	dc := &model.DevContainer{
+		Image:  "ghcr.io/acmecorp/dev:1",
+		CapAdd: []string{"SYS_PTRACE", "sys_admin"},
+		Features: map[string]model.FeatureOptions{
+			"ghcr.io/devcontainers/features/go:1":                {},
+			"ghcr.io/devcontainers/features/node@sha256:abc":     {},
+			"ghcr.io/devcontainers-contrib/features/terraform:1": {},
+		},
+		HostRequirements: &model.HostRequirements{CPUs: 4, Memory: "32gb", Storage: "64gb"},
+		Customizations: &model.Customizations{VSCode: &model.VSCodeCustomization{
+			Extensions: []string{"editorconfig.editorconfig"},
+		}},
+	}
+
+	got := map[string][]string{}
+	for _, d := range p.Evaluate(dc, "config.yaml", nil) {
+		got[d.RuleID] = append(got[d.RuleID], d.Path)
+	}
+	want := map[string][]string{
+		RuleRegistry:   {"image"},
+		RuleFeature:    {`features."ghcr.io/devcontainers-contrib/features/terraform:1"`},
+		RuleCapability: {"capAdd[1]"},
+		RuleHostLimits: {"hostRequirements.memory"},
+		RuleExtension:  {"customizations.vscode.extensions"},
+	}
+	for rule, paths := range want {
+		if len(got[rule]) != len(paths) || got[rule][0] != paths[0] {
+			t.Errorf("%s: got %v, want %v", rule, got[rule], paths)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("unexpected rules: %v", got)
+	}
+}
+
+func TestEvaluateRejectsUnparsableSizes(t *testing.T) {
+	p := &Policy{MaxHostRequirements: &HostLimits{Memory: "16gb", Storage: "64gb"}}
+	dc := &model.DevContainer{HostRequirements: &model.HostRequirements{Memory: "64", Storage: "64G"}}
+
+	var got []string
+	for _, d := range p.Evaluate(dc, "config.yaml", nil) {
+		if d.RuleID != RuleHostLimits {
+			t.Errorf("unexpected rule %s", d.RuleID)
+		}
+		got = append(got, d.Path)
+	}
+	if len(got) != 2 || got[0] != "hostRequirements.memory" || got[1] != "hostRequirements.storage" {
+		t.Errorf("got %v, want violations for memory and storage", got)
+	}
+}
+
+func TestRegistryAllowed(t *testing.T) {
+	p := &Policy{AllowedRegistries: []string{"docker.io/library", "localhost:5000"}}
+	cases := map[string]bool{
+		"ubuntu:22.04":                    true,
+		"docker.io/library/ubuntu":        true,
+		"acme/ubuntu":                     false,
+		"localhost:5000/dev":              true,
+		"mcr.microsoft.com/devcontainers": false,
+	}
+	for image, want := range cases {
+		if got := p.registryAllowed(image); got != want {
+			t.Errorf("registryAllowed(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]float64{"1kb": 1 << 10, "1.5GB": 1.5 * (1 << 30), "2tb": 2 << 40, "512 mb": 512 << 20}
+	for in, want := range cases {
+		if got, err := parseSize(in); err != nil || got != want {
+			t.Errorf("parseSize(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseSize("lots"); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("allowedRegistry: [mcr.microsoft.com]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for misspelled field")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvVar, "")
+
+	if got, err := Resolve("", sub); err != nil || got != "" {
+		t.Errorf("Resolve without policy = %q, %v; want none", got, err)
+	}
+
+	discovered := filepath.Join(root, DefaultFile)
+	if err := os.WriteFile(discovered, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Resolve("", sub); err != nil || got != discovered {
+		t.Errorf("Resolve = %q, %v; want %q", got, err, discovered)
+	}
+
+	t.Setenv(EnvVar, "from-env.yaml")
+	if got, _ := Resolve("", sub); got != "from-env.yaml" {
+		t.Errorf("Resolve with %s = %q", EnvVar, got)
+	}
+	if got, _ := Resolve("from-flag.yaml", sub); got != "from-flag.yaml" {
+		t.Errorf("Resolve with flag = %q", got)
+	}
+}
@@ -1,14 +1,18 @@
 package presets
 
-func dockerComposeFilePresetsMap() map[string][]string {
-	return map[string][]string{
-		"base":     {"docker-compose.yml"},
-		"with-dev": {"docker-compose.yml", "docker-compose.dev.yml"},
+import "github.com/lucasassuncao/devcontainerwizard/internal/model"
+
+func dockerComposeFilePresetsMap() map[string]model.StringOrSlice {
+	return map[string]model.StringOrSlice{
+		"base":     model.SingleString("docker-compose.yml"),
+		"with-dev": model.StringSlice([]string{"docker-compose.yml", "docker-compose.dev.yml"}),
 	}
 }
 
-func DockerComposeFilePreset(name string) []string { return dockerComposeFilePresetsMap()[name] }
-func ListDockerComposeFilePresets() []string       { return sortedKeys(dockerComposeFilePresetsMap()) }
+func DockerComposeFilePreset(name string) model.StringOrSlice {
+	return dockerComposeFilePresetsMap()[name]
+}
+func ListDockerComposeFilePresets() []string { return sortedKeys(dockerComposeFilePresetsMap()) }
 
 func servicePresetsMap() map[string]string {
 	return map[string]string{
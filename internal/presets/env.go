@@ -15,13 +15,20 @@ func containerEnvPresetsMap() map[string]map[string]string {
 func ContainerEnvPreset(name string) map[string]string { return containerEnvPresetsMap()[name] }
 func ListContainerEnvPresets() []string                { return sortedKeys(containerEnvPresetsMap()) }
 
-func remoteEnvPresetsMap() map[string]map[string]string {
-	return map[string]map[string]string{
+func remoteEnvPresetsMap() map[string]map[string]*string {
+	return map[string]map[string]*string{
 		"base": {
-			"PATH": "${containerEnv:PATH}:/usr/local/bin",
+			"PATH": envValue("${containerEnv:PATH}:/usr/local/bin"),
+		},
+		"unset-proxy": {
+			"HTTP_PROXY":  nil,
+			"HTTPS_PROXY": nil,
 		},
 	}
 }
 
-func RemoteEnvPreset(name string) map[string]string { return remoteEnvPresetsMap()[name] }
-func ListRemoteEnvPresets() []string                { return sortedKeys(remoteEnvPresetsMap()) }
+// envValue returns a remoteEnv value; nil stands for null, which unsets the variable.
+func envValue(s string) *string { return &s }
+
+func RemoteEnvPreset(name string) map[string]*string { return remoteEnvPresetsMap()[name] }
+func ListRemoteEnvPresets() []string                 { return sortedKeys(remoteEnvPresetsMap()) }
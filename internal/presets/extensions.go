@@ -2,34 +2,34 @@ package presets
 
 import "github.com/lucasassuncao/devcontainerwizard/internal/model"
 
-func featuresPresetsMap() map[string]map[string]map[string]any {
-	return map[string]map[string]map[string]any{
+func featuresPresetsMap() map[string]map[string]model.FeatureOptions {
+	return map[string]map[string]model.FeatureOptions{
 		"base": {
-			"ghcr.io/devcontainers/features/git:1": {},
+			"ghcr.io/devcontainers/features/git:1": model.FeatureObject(nil),
 		},
 		"common-utils": {
-			"ghcr.io/devcontainers/features/common-utils:2": {
+			"ghcr.io/devcontainers/features/common-utils:2": model.FeatureObject(map[string]any{
 				"installZsh":      true,
 				"installOhMyZsh":  true,
 				"upgradePackages": true,
-			},
+			}),
 		},
 		"docker-in-docker": {
-			"ghcr.io/devcontainers/features/docker-in-docker:2": {
+			"ghcr.io/devcontainers/features/docker-in-docker:2": model.FeatureObject(map[string]any{
 				"version": "latest",
 				"moby":    true,
-			},
+			}),
 		},
 		"go-toolchain": {
-			"ghcr.io/devcontainers/features/go:1": {
+			"ghcr.io/devcontainers/features/go:1": model.FeatureObject(map[string]any{
 				"version": "1.25",
-			},
+			}),
 		},
 	}
 }
 
-func FeaturesPreset(name string) map[string]map[string]any { return featuresPresetsMap()[name] }
-func ListFeaturesPresets() []string                        { return sortedKeys(featuresPresetsMap()) }
+func FeaturesPreset(name string) map[string]model.FeatureOptions { return featuresPresetsMap()[name] }
+func ListFeaturesPresets() []string                              { return sortedKeys(featuresPresetsMap()) }
 
 func overrideFeatureInstallOrderPresetsMap() map[string][]string {
 	return map[string][]string{
@@ -93,6 +93,21 @@ func customizationsPresetsMap() map[string]*model.Customizations {
 				},
 			},
 		},
+		"codespaces-org-read": {
+			Codespaces: &model.CodespacesCustomization{
+				Repositories: map[string]model.CodespacesRepository{
+					"my-org/*": {Permissions: model.PermissionScopesPtr(map[string]string{"contents": "read", "packages": "read"})},
+				},
+				OpenFiles: []string{"README.md"},
+			},
+		},
+		"codespaces-repo-write": {
+			Codespaces: &model.CodespacesCustomization{
+				Repositories: map[string]model.CodespacesRepository{
+					"my-org/my-repo": {Permissions: model.PermissionScopesPtr(map[string]string{"contents": "write", "pull_requests": "write"})},
+				},
+			},
+		},
 		"jetbrains-default": {
 			JetBrains: &model.JetBrainsCustomization{
 				Plugins: []string{"com.intellij.plugins.github"},
@@ -2,25 +2,25 @@ package presets
 
 import "github.com/lucasassuncao/devcontainerwizard/internal/model"
 
-func forwardPortsPresetsMap() map[string][]any {
-	return map[string][]any{
-		"base":      {3000},
-		"web-stack": {3000, 5432, 6379},
-		"node-dev":  {3000, 9229},
+func forwardPortsPresetsMap() map[string][]model.PortSpec {
+	return map[string][]model.PortSpec{
+		"base":      {model.PortNumber(3000)},
+		"web-stack": {model.PortNumber(3000), model.PortNumber(5432), model.PortNumber(6379)},
+		"node-dev":  {model.PortNumber(3000), model.PortNumber(9229)},
 	}
 }
 
-func ForwardPortsPreset(name string) []any { return forwardPortsPresetsMap()[name] }
-func ListForwardPortsPresets() []string    { return sortedKeys(forwardPortsPresetsMap()) }
+func ForwardPortsPreset(name string) []model.PortSpec { return forwardPortsPresetsMap()[name] }
+func ListForwardPortsPresets() []string               { return sortedKeys(forwardPortsPresetsMap()) }
 
-func appPortPresetsMap() map[string][]any {
-	return map[string][]any{
-		"base": {3000},
+func appPortPresetsMap() map[string][]model.PortSpec {
+	return map[string][]model.PortSpec{
+		"base": {model.PortNumber(3000)},
 	}
 }
 
-func AppPortPreset(name string) []any { return appPortPresetsMap()[name] }
-func ListAppPortPresets() []string    { return sortedKeys(appPortPresetsMap()) }
+func AppPortPreset(name string) []model.PortSpec { return appPortPresetsMap()[name] }
+func ListAppPortPresets() []string               { return sortedKeys(appPortPresetsMap()) }
 
 func portsAttributesPresetsMap() map[string]map[string]*model.PortAttributes {
 	return map[string]map[string]*model.PortAttributes{